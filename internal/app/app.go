@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pranshuparmar/witr/internal/output"
 	procpkg "github.com/pranshuparmar/witr/internal/proc"
@@ -57,6 +60,15 @@ func _genExamples() string {
   # Show only warnings (suspicious env, arguments, parents)
   witr docker --warnings
 
+  # Show only warnings of severity warn or higher
+  witr docker --warnings --min-severity warn
+
+  # Exit non-zero if any critical warning is found (CI, health scripts)
+  witr --port 8080 --fail-on critical
+
+  # Also list warnings hidden by the allowlist
+  witr nginx --warnings --allowlist /etc/witr/allowlist.json --show-suppressed
+
   # Display only environment variables of the process
   witr node --env
 
@@ -72,8 +84,17 @@ func _genExamples() string {
   # Show extended process information (memory, I/O, file descriptors)
   witr mysql --verbose
 
+  # List open files, sockets and pipes (with the process on the other end)
+  witr --pid 1234 --fds
+
+  # Find a port bound inside any container's network namespace
+  witr --port 8080 --netns all
+
   # Combine flags: inspect port, show environment variables, output JSON
   witr --port 8080 --env --json
+
+  # Explain a process that already exited (needs witr record running)
+  witr --pid 4242 --history
 `
 }
 
@@ -112,6 +133,16 @@ func init() {
 	rootCmd.Flags().Bool("no-color", false, "disable colorized output")
 	rootCmd.Flags().Bool("env", false, "show environment variables for the process")
 	rootCmd.Flags().Bool("verbose", false, "show extended process information")
+	rootCmd.Flags().Bool("fds", false, "show open file descriptors grouped by type")
+	rootCmd.Flags().Duration("cpu-interval", 250*time.Millisecond, "how long to measure CPU usage for with --verbose (Linux)")
+	rootCmd.Flags().Duration("timeout", 0, "stop scanning processes after this long and report partial results (default no limit)")
+	rootCmd.Flags().String("min-severity", "info", "only show warnings at or above this severity (info|warn|critical)")
+	rootCmd.Flags().String("fail-on", "", "exit non-zero if any warning is at or above this severity (warn|critical)")
+	rootCmd.Flags().String("allowlist", "", "warning allowlist file (default ~/.config/witr/allowlist.json if present)")
+	rootCmd.Flags().Bool("show-suppressed", false, "also show warnings hidden by the allowlist")
+	rootCmd.Flags().String("netns", "", "network namespaces to search for --port: host, all or a container name/ID (default host, then all)")
+	rootCmd.Flags().Bool("history", false, "explain the process from the history kept by witr record, even if it exited")
+	rootCmd.Flags().String("history-file", "", "history file to read with --history (default as for witr record)")
 
 }
 
@@ -130,6 +161,37 @@ func runApp(cmd *cobra.Command, args []string) error {
 	warnFlag, _ := cmd.Flags().GetBool("warnings")
 	noColorFlag, _ := cmd.Flags().GetBool("no-color")
 	verboseFlag, _ := cmd.Flags().GetBool("verbose")
+	fdsFlag, _ := cmd.Flags().GetBool("fds")
+	cpuIntervalFlag, _ := cmd.Flags().GetDuration("cpu-interval")
+	timeoutFlag, _ := cmd.Flags().GetDuration("timeout")
+	minSeverityFlag, _ := cmd.Flags().GetString("min-severity")
+	failOnFlag, _ := cmd.Flags().GetString("fail-on")
+	allowlistFlag, _ := cmd.Flags().GetString("allowlist")
+	showSuppressedFlag, _ := cmd.Flags().GetBool("show-suppressed")
+	netnsFlag, _ := cmd.Flags().GetString("netns")
+	historyFlag, _ := cmd.Flags().GetBool("history")
+	historyFileFlag, _ := cmd.Flags().GetString("history-file")
+
+	minSeverity := model.Severity(minSeverityFlag)
+	if minSeverity.Rank() == 0 {
+		return fmt.Errorf("invalid --min-severity %q: must be one of info, warn, critical", minSeverityFlag)
+	}
+	failOn := model.Severity(failOnFlag)
+	if failOnFlag != "" && failOn != model.SeverityWarn && failOn != model.SeverityCritical {
+		return fmt.Errorf("invalid --fail-on %q: must be one of warn, critical", failOnFlag)
+	}
+	if cpuIntervalFlag <= 0 || cpuIntervalFlag > time.Minute {
+		return fmt.Errorf("invalid --cpu-interval %s: must be above 0 and at most 1m", cpuIntervalFlag)
+	}
+	if timeoutFlag < 0 {
+		return fmt.Errorf("invalid --timeout %s: must not be negative", timeoutFlag)
+	}
+	if timeoutFlag > 0 {
+		procpkg.Snapshot().SetDeadline(time.Now().Add(timeoutFlag))
+	}
+	if netnsFlag != "" && runtime.GOOS != "linux" {
+		return fmt.Errorf("--netns is only supported on Linux")
+	}
 
 	outw := cmd.OutOrStdout()
 	outp := output.NewPrinter(outw)
@@ -140,7 +202,7 @@ func runApp(cmd *cobra.Command, args []string) error {
 		case pidFlag != "":
 			t = model.Target{Type: model.TargetPID, Value: pidFlag}
 		case portFlag != "":
-			t = model.Target{Type: model.TargetPort, Value: portFlag}
+			t = model.Target{Type: model.TargetPort, Value: portFlag, NetNS: netnsFlag}
 		case len(args) > 0:
 			t = model.Target{Type: model.TargetName, Value: args[0]}
 		default:
@@ -155,7 +217,14 @@ func runApp(cmd *cobra.Command, args []string) error {
 			outp.Print("Multiple matching processes found:\n\n")
 			for i, pid := range pids {
 				cmdline := procpkg.GetCmdline(pid)
-				outp.Printf("[%d] PID %d   %s\n", i+1, pid, cmdline)
+				if app := procpkg.DetectApp(procpkg.CmdlineArgs(pid, cmdline)); app != "" {
+					cmdline = app + " (" + cmdline + ")"
+				}
+				if label := procpkg.NetNamespaceLabel(pid); label != "" {
+					outp.Printf("[%d] PID %d   %s   (%s)\n", i+1, pid, cmdline, label)
+				} else {
+					outp.Printf("[%d] PID %d   %s\n", i+1, pid, cmdline)
+				}
 			}
 			outp.Println("\nRe-run with:")
 			outp.Println("  witr --pid <pid> --env")
@@ -183,13 +252,31 @@ func runApp(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if historyFlag {
+		if portFlag != "" {
+			return fmt.Errorf("--history needs --pid or a process name, not --port")
+		}
+		return runHistory(cmd, pidFlag, args, historyFile(historyFileFlag), jsonFlag, noColorFlag)
+	}
+
+	var allowlist *source.Allowlist
+	var err error
+	if allowlistFlag != "" {
+		allowlist, err = source.LoadAllowlist(allowlistFlag)
+	} else {
+		allowlist, err = source.LoadDefaultAllowlist()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load allowlist: %w", err)
+	}
+
 	var t model.Target
 
 	switch {
 	case pidFlag != "":
 		t = model.Target{Type: model.TargetPID, Value: pidFlag}
 	case portFlag != "":
-		t = model.Target{Type: model.TargetPort, Value: portFlag}
+		t = model.Target{Type: model.TargetPort, Value: portFlag, NetNS: netnsFlag}
 	case len(args) > 0:
 		t = model.Target{Type: model.TargetName, Value: args[0]}
 	default:
@@ -205,9 +292,18 @@ func runApp(cmd *cobra.Command, args []string) error {
 		var errorMsg string
 		if strings.Contains(errStr, "socket found but owning process not detected") {
 			errorMsg = fmt.Sprintf("%s\n\nA socket was found for the port, but the owning process could not be detected.\nThis may be due to insufficient permissions. Try running with sudo:\n  sudo %s", errStr, strings.Join(os.Args, " "))
+			if n := procpkg.Snapshot().Denied(); n > 0 {
+				errorMsg += fmt.Sprintf("\n(the open sockets of %d processes could not be read)", n)
+			}
 		} else {
 			errorMsg = fmt.Sprintf("%s\n\nNo matching process or service found. Please check your query or try a different name/port/PID.\nFor usage and options, run: witr --help", errStr)
 		}
+		if t.Type == model.TargetPID {
+			errorMsg += historyHint(pidFlag, historyFileFlag)
+		}
+		if n := procpkg.Snapshot().Incomplete(); n > 0 {
+			errorMsg += "\n\n" + output.ScanIncompleteNote(n)
+		}
 		return errors.New(errorMsg)
 	}
 
@@ -215,7 +311,17 @@ func runApp(cmd *cobra.Command, args []string) error {
 		outp.Print("Multiple matching processes found:\n\n")
 		for i, pid := range pids {
 			cmdline := procpkg.GetCmdline(pid)
-			outp.Printf("[%d] PID %d   %s\n", i+1, pid, cmdline)
+			if app := procpkg.DetectApp(procpkg.CmdlineArgs(pid, cmdline)); app != "" {
+				cmdline = app + " (" + cmdline + ")"
+			}
+			if label := procpkg.NetNamespaceLabel(pid); label != "" {
+				outp.Printf("[%d] PID %d   %s   (%s)\n", i+1, pid, cmdline, label)
+			} else {
+				outp.Printf("[%d] PID %d   %s\n", i+1, pid, cmdline)
+			}
+		}
+		if n := procpkg.Snapshot().Incomplete(); n > 0 {
+			outp.Printf("\nNote: %s\n", output.ScanIncompleteNote(n))
 		}
 		outp.Println("\nRe-run with:")
 		outp.Println("  witr --pid <pid>")
@@ -228,10 +334,14 @@ func runApp(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		errStr := err.Error()
 		errorMsg := fmt.Sprintf("%s\n\nNo matching process or service found. Please check your query or try a different name/port/PID.\nFor usage and options, run: witr --help", errStr)
+		if t.Type == model.TargetPID {
+			errorMsg += historyHint(pidFlag, historyFileFlag)
+		}
 		return errors.New(errorMsg)
 	}
 
 	src := source.Detect(ancestry)
+	session := source.DetectSession(ancestry, procpkg.GetCmdline)
 
 	var proc model.Process
 	resolvedTarget := "unknown"
@@ -240,7 +350,14 @@ func runApp(cmd *cobra.Command, args []string) error {
 		resolvedTarget = proc.Command
 	}
 
-	if verboseFlag && len(ancestry) > 0 {
+	if len(ancestry) > 0 {
+		proc.Binary = procpkg.ReadBinaryInfo(proc.PID, proc.Exe, verboseFlag)
+		proc.Lineage = procpkg.ReadLineage(ancestry)
+		proc.MemoryCgroup = procpkg.ReadCgroupMemory(proc.PID, proc.Command, verboseFlag)
+		ancestry[len(ancestry)-1] = proc
+	}
+
+	if (verboseFlag || fdsFlag) && len(ancestry) > 0 {
 		memInfo, ioStats, fileDescs, fdCount, fdLimit, children, threadCount, err := procpkg.ReadExtendedInfo(pid)
 		if err == nil {
 			proc.Memory = memInfo
@@ -250,16 +367,21 @@ func runApp(cmd *cobra.Command, args []string) error {
 			proc.FDLimit = fdLimit
 			proc.Children = children
 			proc.ThreadCount = threadCount
+			if fds := procpkg.ReadFileDescriptors(pid); len(fds) > 0 {
+				proc.FDs = fds
+				proc.FDCounts = model.CountFDs(fds)
+			}
 			ancestry[len(ancestry)-1] = proc
 		}
 	}
 
 	var resCtx *model.ResourceContext
-	var fileCtx *model.FileContext
 	if verboseFlag {
-		resCtx = procpkg.GetResourceContext(pid)
-		fileCtx = procpkg.GetFileContext(pid)
+		resCtx = procpkg.GetResourceContext(pid, cpuIntervalFlag)
 	}
+	// The fd count feeds the fd-limit warning, so it is always read; the
+	// locks and inotify watches only for --verbose
+	fileCtx := procpkg.GetFileContext(pid, verboseFlag)
 
 	var childProcesses []model.Process
 	if (verboseFlag || treeFlag) && proc.PID > 0 {
@@ -268,26 +390,45 @@ func runApp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Calculate restart count (consecutive same-command entries)
-	restartCount := 0
-	lastCmd := ""
-	for _, procA := range ancestry {
-		if procA.Command == lastCmd {
-			restartCount++
+	// Restarts as counted by the supervisor. Nested same-name ancestors are
+	// reported on their own, they are not restarts.
+	restartCount, restartSource := supervisorRestarts(src, proc.PID)
+
+	subject := source.AllowlistSubject{
+		Units: []string{proc.Service, src.Name},
+		Exe:   proc.Exe,
+		Ports: proc.ListeningPorts,
+	}
+	if subject.Exe == "" {
+		if fields := strings.Fields(proc.Cmdline); len(fields) > 0 {
+			subject.Exe = fields[0]
 		}
-		lastCmd = procA.Command
 	}
+	if allowlist.NeedsImage() {
+		subject.Image = procpkg.ContainerImage(proc.PID)
+	}
+	warnings := append(source.Warnings(ancestry), source.RestartWarnings(restartCount, restartSource)...)
+	warnings, suppressed := allowlist.Apply(append(warnings, source.FileWarnings(fileCtx)...), subject, time.Now())
 
 	res := model.Result{
-		Target:          t,
-		ResolvedTarget:  resolvedTarget,
-		Process:         proc,
-		RestartCount:    restartCount,
-		Ancestry:        ancestry,
-		Source:          src,
-		Warnings:        source.Warnings(ancestry),
-		ResourceContext: resCtx,
-		FileContext:     fileCtx,
+		Target:            t,
+		ResolvedTarget:    resolvedTarget,
+		Process:           proc,
+		RestartCount:      restartCount,
+		RestartSource:     restartSource,
+		SupervisorStarted: source.SupervisorStarted(src, ancestry),
+		NestedSameName:    source.NestedSameName(ancestry),
+		Ancestry:          ancestry,
+		Source:            src,
+		Warnings:          warnings,
+		ResourceContext:   resCtx,
+		Session:           session,
+	}
+	if verboseFlag {
+		res.FileContext = fileCtx
+	}
+	if showSuppressedFlag {
+		res.Suppressed = source.FilterWarnings(suppressed, minSeverity)
 	}
 	if len(childProcesses) > 0 {
 		res.ChildProcesses = childProcesses
@@ -302,6 +443,12 @@ func runApp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	res.ScanIncomplete = procpkg.Snapshot().Incomplete()
+	res.ScanDenied = procpkg.Snapshot().Denied()
+
+	allWarnings := res.Warnings
+	res.Warnings = source.FilterWarnings(res.Warnings, minSeverity)
+
 	if jsonFlag {
 		importJSON, err := output.ToJSON(res)
 		if err != nil {
@@ -309,7 +456,9 @@ func runApp(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Fprintln(outw, importJSON)
 	} else if warnFlag {
-		output.RenderWarnings(outw, res.Warnings, !noColorFlag)
+		output.RenderWarnings(outw, res.Warnings, res.Suppressed, !noColorFlag)
+	} else if fdsFlag {
+		output.RenderFDs(outw, res, !noColorFlag)
 	} else if treeFlag {
 		output.PrintTree(outw, res.Ancestry, res.ChildProcesses, !noColorFlag)
 	} else if shortFlag {
@@ -317,9 +466,34 @@ func runApp(cmd *cobra.Command, args []string) error {
 	} else {
 		output.RenderStandard(outw, res, !noColorFlag, verboseFlag)
 	}
+
+	if failOnFlag != "" {
+		if failing := source.FilterWarnings(allWarnings, failOn); len(failing) > 0 {
+			return fmt.Errorf("%d warning(s) at or above severity %s", len(failing), failOn)
+		}
+	}
 	return nil
 }
 
+// supervisorRestarts asks whatever restarts the process how often it did:
+// pm2's dump, docker or podman for containers, systemd for services
+func supervisorRestarts(src model.Source, pid int) (int, string) {
+	if n, err := strconv.Atoi(src.Details["restarts"]); err == nil {
+		return n, src.Name + " restart_time"
+	}
+	switch src.Type {
+	case model.SourceContainer:
+		if n, engine, ok := procpkg.ContainerRestartCount(pid); ok {
+			return n, engine + " RestartCount"
+		}
+	case model.SourceSystemd:
+		if n, unit, ok := procpkg.UnitRestarts(pid); ok {
+			return n, "systemd NRestarts of " + unit
+		}
+	}
+	return 0, ""
+}
+
 func Root() *cobra.Command { return rootCmd }
 
 func SetVersionBuildCommitString(Version string, Commit string, BuildDate string) {
@@ -0,0 +1,71 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Host-wide checks across all processes",
+}
+
+var auditStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List processes running deleted or replaced binaries and libraries",
+	Long: "stale lists every process whose executable or mapped shared libraries were deleted or\n" +
+		"replaced on disk (typically by a package upgrade) and therefore needs a restart. Linux only.",
+	Example: `
+  # Find services that still run pre-upgrade binaries
+  sudo witr audit stale
+
+  # Machine-readable output
+  witr audit stale --json
+`,
+	Args: cobra.NoArgs,
+	RunE: runAuditStale,
+}
+
+func init() {
+	auditStaleCmd.Flags().Bool("json", false, "show result as JSON")
+	auditStaleCmd.Flags().Bool("no-color", false, "disable colorized output")
+	auditCmd.AddCommand(auditStaleCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAuditStale(cmd *cobra.Command, args []string) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	noColorFlag, _ := cmd.Flags().GetBool("no-color")
+
+	stale, err := procpkg.ListStaleProcesses()
+	if err != nil {
+		return err
+	}
+	skipped := procpkg.Snapshot().Denied() + procpkg.Snapshot().Incomplete()
+
+	outw := cmd.OutOrStdout()
+	if jsonFlag {
+		if stale == nil {
+			stale = []model.StaleProcess{}
+		}
+		data, err := json.MarshalIndent(stale, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate json output: %w", err)
+		}
+		fmt.Fprintln(outw, string(data))
+		if skipped > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "witr: %s\n", output.StaleSkippedNote(skipped))
+		}
+		return nil
+	}
+
+	output.RenderStaleAudit(outw, stale, skipped, !noColorFlag)
+	return nil
+}
@@ -0,0 +1,96 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/history"
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// historyFile returns the history file to read: the --history-file flag,
+// else the current user's, else the system-wide one
+func historyFile(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	file := history.DefaultPath()
+	if _, err := os.Stat(file); err != nil {
+		if _, err := os.Stat(history.SystemPath); err == nil {
+			return history.SystemPath
+		}
+	}
+	return file
+}
+
+// runHistory explains a PID from the events recorded by `witr record`
+func runHistory(cmd *cobra.Command, pidFlag string, args []string, file string, jsonFlag, noColorFlag bool) error {
+	events, err := history.ReadEvents(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no history file at %s\n\nStart recording with: witr record", file)
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("cannot read history file %s: permission denied\n\nTry running with sudo:\n  sudo %s", file, strings.Join(os.Args, " "))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var pid int
+	switch {
+	case pidFlag != "":
+		pid, err = strconv.Atoi(pidFlag)
+		if err != nil || pid <= 0 {
+			return fmt.Errorf("invalid pid: %s", pidFlag)
+		}
+	case len(args) > 0:
+		ev, ok := history.LastExec(events, args[0])
+		if !ok {
+			return fmt.Errorf("no process named %s in the recorded history", args[0])
+		}
+		pid = ev.PID
+	default:
+		return fmt.Errorf("--history needs --pid or a process name")
+	}
+
+	runs := history.Runs(events, pid)
+	if len(runs) == 0 {
+		return fmt.Errorf("no history recorded for pid %d in %s\n\nwitr record must be running when the process starts", pid, file)
+	}
+	h := model.ProcessHistory{PID: pid, Runs: runs}
+	h.Running = pidRunning(pid)
+
+	outw := cmd.OutOrStdout()
+	if jsonFlag {
+		data, err := json.MarshalIndent(h, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate json output: %w", err)
+		}
+		fmt.Fprintln(outw, string(data))
+		return nil
+	}
+	output.RenderHistory(outw, h, !noColorFlag)
+	return nil
+}
+
+// historyHint points at why-exited for a PID that is gone, and at
+// --history when witr record saw it
+func historyHint(pidFlag, fileFlag string) string {
+	pid, err := strconv.Atoi(pidFlag)
+	if err != nil || pid <= 0 {
+		return ""
+	}
+	events, err := history.ReadEvents(historyFile(fileFlag))
+	if err != nil || len(history.Runs(events, pid)) == 0 {
+		return fmt.Sprintf("\n\nIf the process exited, find out why with:\n  witr why-exited %d", pid)
+	}
+	return fmt.Sprintf("\n\nThe process was recorded by witr record. Explain it with:\n  witr --pid %d --history\n  witr why-exited %d", pid, pid)
+}
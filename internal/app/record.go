@@ -0,0 +1,82 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record process execs and exits so exited processes can be explained later",
+	Long: "record keeps a bounded on-disk history of every exec and exit, with the ancestry at exec\n" +
+		"time, so that `witr --pid <pid> --history` can explain a process after it is gone.\n" +
+		"Events come from the netlink proc connector (needs root or CAP_NET_ADMIN); without it\n" +
+		"/proc is polled, which misses processes shorter-lived than the polling interval.\n" +
+		"The history file is a ring buffer: once full, the oldest events are overwritten. Linux only.",
+	Example: `
+  # Record system-wide (run as a service or in the background)
+  sudo witr record
+
+  # Explain a PID that has since exited
+  sudo witr --pid 4242 --history
+
+  # Unprivileged recording, polling /proc every 50ms into a custom file
+  witr record --poll --interval 50ms --file /tmp/witr-history
+`,
+	Args: cobra.NoArgs,
+	RunE: runRecord,
+}
+
+func init() {
+	recordCmd.Flags().String("file", "", "history file (default /var/lib/witr/history as root, else ~/.local/state/witr/history)")
+	recordCmd.Flags().Int("max-events", history.DefaultSlots, "number of events kept before the oldest are overwritten")
+	recordCmd.Flags().Bool("poll", false, "poll /proc instead of using the proc connector")
+	recordCmd.Flags().Duration("interval", 100*time.Millisecond, "polling interval")
+	rootCmd.AddCommand(recordCmd)
+}
+
+func runRecord(cmd *cobra.Command, args []string) error {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	maxEventsFlag, _ := cmd.Flags().GetInt("max-events")
+	pollFlag, _ := cmd.Flags().GetBool("poll")
+	intervalFlag, _ := cmd.Flags().GetDuration("interval")
+
+	if maxEventsFlag <= 0 {
+		return fmt.Errorf("invalid --max-events %d: must be positive", maxEventsFlag)
+	}
+	if intervalFlag <= 0 {
+		return fmt.Errorf("invalid --interval %s: must be positive", intervalFlag)
+	}
+	file := fileFlag
+	if file == "" {
+		file = history.DefaultPath()
+	}
+
+	store, err := history.Open(file, maxEventsFlag)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errw := cmd.ErrOrStderr()
+	fmt.Fprintf(errw, "witr: recording process history to %s (up to %d events)\n", file, maxEventsFlag)
+	return history.Record(ctx, store, history.Options{
+		Poll:     pollFlag,
+		Interval: intervalFlag,
+		Logf: func(format string, args ...any) {
+			fmt.Fprintf(errw, "witr: "+format+"\n", args...)
+		},
+	})
+}
@@ -0,0 +1,178 @@
+//go:build linux || darwin || freebsd || windows
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/history"
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+var whyExitedCmd = &cobra.Command{
+	Use:   "why-exited <pid|unit>",
+	Short: "Explain why a process or systemd unit stopped running",
+	Long: "why-exited reports how a process or the main process of a systemd unit ended: the exit\n" +
+		"code or signal recorded by witr record, the unit's last state change and result, and any\n" +
+		"OOM kill of it in the kernel log (/dev/kmsg, /var/log/kern.log, /var/log/messages). Units\n" +
+		"systemd no longer knows are read from the journal files on disk. Linux only.",
+	Example: `
+  # Why did PID 4242 go away? (best with witr record running)
+  sudo witr why-exited 4242
+
+  # Why is a service not running any more?
+  witr why-exited nginx
+
+  # Machine-readable output
+  witr why-exited myapp.service --json
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWhyExited,
+}
+
+func init() {
+	whyExitedCmd.Flags().Bool("json", false, "show result as JSON")
+	whyExitedCmd.Flags().Bool("no-color", false, "disable colorized output")
+	whyExitedCmd.Flags().String("history-file", "", "history file written by witr record (default as for witr record)")
+	rootCmd.AddCommand(whyExitedCmd)
+}
+
+func runWhyExited(cmd *cobra.Command, args []string) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+	noColorFlag, _ := cmd.Flags().GetBool("no-color")
+	historyFileFlag, _ := cmd.Flags().GetString("history-file")
+
+	events, _ := history.ReadEvents(historyFile(historyFileFlag))
+	oomKills := procpkg.ReadOOMKills()
+
+	report := model.ExitReport{Target: args[0]}
+	unit := ""
+	if pid, err := strconv.Atoi(args[0]); err == nil {
+		if pid <= 0 {
+			return fmt.Errorf("invalid pid: %s", args[0])
+		}
+		report.Target = "pid " + args[0]
+		report.PID = pid
+		report.Run = lastRun(events, pid)
+		// Kills and log lines of earlier processes that had the PID are
+		// not this one's
+		report.OOMKills = source.OOMKillsDuring(oomKillsOf(oomKills, pid, ""), report.Run)
+		for _, k := range report.OOMKills {
+			if u := source.UnitFromCgroup(k.TaskCgroup); u != "" {
+				unit = u
+			}
+		}
+		// Without a recorded start any process that ever had the PID
+		// could have logged the lines, so the journal is not asked
+		if start := source.RunStart(report.Run); unit == "" && !start.IsZero() {
+			unit = procpkg.JournalUnitOf(pid, start)
+		}
+	} else {
+		unit = args[0]
+		if !strings.Contains(unit, ".") {
+			unit += ".service"
+		}
+		report.Target = unit
+	}
+
+	if unit != "" {
+		state, err := procpkg.ReadUnitState(unit)
+		if err != nil {
+			// Transient and garbage-collected units are only in the journal
+			state = procpkg.JournalUnitState(unit)
+		}
+		if state == nil && report.PID == 0 {
+			return err
+		}
+		report.Unit = state
+		if state != nil && report.PID == 0 {
+			report.PID = state.ExecMainPID
+			// Kills in the unit's cgroup before its last start are old news
+			for _, k := range oomKillsOf(oomKills, state.ExecMainPID, unit) {
+				if k.PID == state.ExecMainPID || state.ExecMainStart.IsZero() || !k.Time.Before(state.ExecMainStart) {
+					report.OOMKills = append(report.OOMKills, k)
+				}
+			}
+		}
+	}
+
+	if report.PID > 0 && report.Run == nil {
+		report.Run = lastRun(events, report.PID)
+	}
+
+	switch {
+	case report.Run != nil && len(report.Run.Execs) > 0:
+		report.Command = report.Run.Execs[len(report.Run.Execs)-1].Command
+	case report.Run != nil && report.Run.Exit != nil:
+		report.Command = report.Run.Exit.Command
+	case len(report.OOMKills) > 0:
+		report.Command = report.OOMKills[len(report.OOMKills)-1].Command
+	}
+
+	if report.Unit != nil {
+		report.Running = report.Unit.ActiveState == "active" || report.Unit.ActiveState == "reloading"
+	} else {
+		report.Running = pidRunning(report.PID)
+	}
+
+	if report.Run == nil && report.Unit == nil && len(report.OOMKills) == 0 {
+		msg := fmt.Sprintf("nothing is known about how pid %d ended: no OOM kill is logged for it", report.PID)
+		if events == nil {
+			msg += " and witr record was not running\n\nRecord process history for next time with: sudo witr record"
+		} else {
+			msg += " and witr record did not see it"
+		}
+		if report.Running {
+			msg = fmt.Sprintf("pid %d is running: witr --pid %d", report.PID, report.PID)
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	report.Reason = source.ExitReason(report)
+
+	outw := cmd.OutOrStdout()
+	if jsonFlag {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to generate json output: %w", err)
+		}
+		fmt.Fprintln(outw, string(data))
+		return nil
+	}
+	output.RenderExitReport(outw, report, !noColorFlag)
+	return nil
+}
+
+// lastRun returns the latest recorded run of pid that ended; a run without
+// an exit is still going (or its exit was missed)
+func lastRun(events []model.HistoryEvent, pid int) *model.HistoryRun {
+	runs := history.Runs(events, pid)
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].Exit != nil || i == len(runs)-1 && !pidRunning(pid) {
+			return &runs[i]
+		}
+	}
+	return nil
+}
+
+func pidRunning(pid int) bool {
+	_, err := procpkg.ReadProcess(pid)
+	return err == nil
+}
+
+// oomKillsOf picks the OOM kills of pid, or of any process in unit
+func oomKillsOf(kills []model.OOMKill, pid int, unit string) []model.OOMKill {
+	var out []model.OOMKill
+	for _, k := range kills {
+		if (pid > 0 && k.PID == pid) || (unit != "" && source.UnitFromCgroup(k.TaskCgroup) == unit) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
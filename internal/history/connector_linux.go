@@ -0,0 +1,175 @@
+//go:build linux
+
+package history
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// Netlink proc connector (linux/cn_proc.h, linux/connector.h)
+const (
+	cnIdxProc         = 1
+	cnValProc         = 1
+	procCnMcastListen = 1
+
+	procEventNone = 0x0
+	procEventFork = 0x1
+	procEventExec = 0x2
+	procEventExit = 0x80000000
+
+	nlmsgHeaderLen = 16
+	cnMsgLen       = 20
+	// what, cpu and timestamp precede the event data
+	procEventHeaderLen = 16
+)
+
+// ackTimeout bounds the wait for the kernel to confirm the subscription
+const ackTimeout = 2 * time.Second
+
+// openConnector subscribes to process events. seq identifies the
+// subscription request, for telling its acknowledgement from the ones
+// other listeners' requests get.
+func openConnector() (fd int, seq uint32, err error) {
+	fd, err = syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.NETLINK_CONNECTOR)
+	if err != nil {
+		return -1, 0, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		syscall.Close(fd)
+		return -1, 0, err
+	}
+
+	ne := binary.NativeEndian
+	msg := make([]byte, nlmsgHeaderLen+cnMsgLen+4)
+	ne.PutUint32(msg[0:], uint32(len(msg)))
+	ne.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	ne.PutUint32(msg[16:], cnIdxProc)
+	ne.PutUint32(msg[20:], cnValProc)
+	seq = uint32(time.Now().UnixNano())
+	ne.PutUint32(msg[24:], seq)
+	ne.PutUint16(msg[32:], 4)
+	ne.PutUint32(msg[36:], procCnMcastListen)
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return -1, 0, err
+	}
+
+	// A receive timeout lets the event loop notice cancellation
+	tv := syscall.NsecToTimeval(time.Second.Nanoseconds())
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		syscall.Close(fd)
+		return -1, 0, err
+	}
+	return fd, seq, nil
+}
+
+// subscriptionAck reads the acknowledgement of the subscription request
+// sent with seq from a connector message: the kernel echoes the request's
+// seq and answers its ack (0) plus one. The acknowledgement goes to every
+// listener, so ok is false for one answering another process's request.
+func subscriptionAck(data []byte, seq uint32) (errno syscall.Errno, ok bool) {
+	if len(data) < cnMsgLen+procEventHeaderLen+4 {
+		return 0, false
+	}
+	ne := binary.NativeEndian
+	if ne.Uint32(data[cnMsgLen:]) != procEventNone || ne.Uint32(data[8:]) != seq || ne.Uint32(data[12:]) != 1 {
+		return 0, false
+	}
+	return syscall.Errno(ne.Uint32(data[cnMsgLen+procEventHeaderLen:])), true
+}
+
+// listen records events from the proc connector. The kernel acknowledges
+// the subscription with an error code; when that is a refusal (no
+// CAP_NET_ADMIN, not the initial user namespace) or never arrives, listen
+// falls back to polling.
+func (r *recorder) listen(ctx context.Context, fd int, seq uint32, opts Options) error {
+	defer syscall.Close(fd)
+
+	acked := false
+	ackDeadline := time.Now().Add(ackTimeout)
+	lastPrune := time.Now()
+	buf := make([]byte, 64*1024)
+	for ctx.Err() == nil {
+		if time.Since(lastPrune) > exitedRetention/2 {
+			r.prune()
+			lastPrune = time.Now()
+		}
+
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			switch {
+			case errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR):
+				if !acked && time.Now().After(ackDeadline) {
+					opts.Logf("proc connector did not confirm the subscription, polling /proc instead")
+					return r.poll(ctx, opts.Interval)
+				}
+				continue
+			case errors.Is(err, syscall.ENOBUFS):
+				opts.Logf("proc connector overflow, some events were lost")
+				continue
+			}
+			return fmt.Errorf("proc connector: %w", err)
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, m := range msgs {
+			data := m.Data
+			if len(data) < cnMsgLen+procEventHeaderLen {
+				continue
+			}
+			ev := data[cnMsgLen:]
+			what := binary.NativeEndian.Uint32(ev)
+			ev = ev[procEventHeaderLen:]
+			if what == procEventNone {
+				// Acknowledgement of a subscription, carrying an errno.
+				// Only ours counts, and only until it arrived.
+				if errno, ok := subscriptionAck(data, seq); ok && !acked {
+					if errno != 0 {
+						opts.Logf("proc connector refused the subscription (%v), polling /proc instead", errno)
+						return r.poll(ctx, opts.Interval)
+					}
+					acked = true
+				}
+				continue
+			}
+			acked = true
+			if err := r.handleEvent(what, ev); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// handleEvent applies one proc connector event. Thread creation and exit
+// (pid != tgid) are ignored.
+func (r *recorder) handleEvent(what uint32, ev []byte) error {
+	u32 := func(i int) int { return int(binary.NativeEndian.Uint32(ev[i*4:])) }
+	switch what {
+	case procEventFork:
+		// parent pid, parent tgid, child pid, child tgid
+		if len(ev) >= 16 && u32(2) == u32(3) {
+			r.fork(u32(1), u32(3))
+		}
+	case procEventExec:
+		// pid, tgid
+		if len(ev) >= 8 && u32(0) == u32(1) {
+			return r.exec(u32(1))
+		}
+	case procEventExit:
+		// pid, tgid, exit code (wait status), exit signal
+		if len(ev) >= 12 && u32(0) == u32(1) {
+			status := u32(2)
+			return r.exit(u32(1), &status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package history
+
+import (
+	"encoding/binary"
+	"syscall"
+	"testing"
+)
+
+func TestSubscriptionAck(t *testing.T) {
+	ack := func(what, seq, ack, errno uint32) []byte {
+		data := make([]byte, cnMsgLen+procEventHeaderLen+4)
+		ne := binary.NativeEndian
+		ne.PutUint32(data[8:], seq)
+		ne.PutUint32(data[12:], ack)
+		ne.PutUint32(data[cnMsgLen:], what)
+		ne.PutUint32(data[cnMsgLen+procEventHeaderLen:], errno)
+		return data
+	}
+	tests := []struct {
+		name      string
+		data      []byte
+		wantErrno syscall.Errno
+		wantOK    bool
+	}{
+		{"ours", ack(procEventNone, 42, 1, 0), 0, true},
+		{"ours refused", ack(procEventNone, 42, 1, uint32(syscall.EPERM)), syscall.EPERM, true},
+		{"another listener's", ack(procEventNone, 7, 1, uint32(syscall.EPERM)), 0, false},
+		{"not an answer", ack(procEventNone, 42, 0, 0), 0, false},
+		{"an event", ack(procEventExec, 42, 1, 0), 0, false},
+		{"short", ack(procEventNone, 42, 1, 0)[:cnMsgLen], 0, false},
+	}
+	for _, tt := range tests {
+		errno, ok := subscriptionAck(tt.data, 42)
+		if errno != tt.wantErrno || ok != tt.wantOK {
+			t.Errorf("%s: subscriptionAck() = %v, %v, want %v, %v", tt.name, errno, ok, tt.wantErrno, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package history
+
+import "os"
+
+// lockFile is a no-op: witr record only runs on Linux
+func lockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build linux
+
+package history
+
+import (
+	"context"
+	"time"
+)
+
+// kthreadd is the parent of all kernel threads
+const kthreadd = 2
+
+// poll diffs /proc every interval: new PIDs (or reused ones, told apart by
+// start time) are execs, a changed command is an exec in place, vanished
+// PIDs are exits with an unknown status
+func (r *recorder) poll(ctx context.Context, interval time.Duration) error {
+	seen := make(map[int]procStat)
+	for _, pid := range listPIDs() {
+		if st, ok := readStat(pid); ok && pid != kthreadd && st.ppid != kthreadd {
+			seen[pid] = st
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current := make(map[int]procStat, len(seen))
+		for _, pid := range listPIDs() {
+			st, ok := readStat(pid)
+			if !ok || pid == kthreadd || st.ppid == kthreadd {
+				// Kernel threads rename themselves, they never exec
+				continue
+			}
+			current[pid] = st
+			old, known := seen[pid]
+			switch {
+			case known && old.startTicks == st.startTicks && old.comm == st.comm:
+				continue
+			case known && old.startTicks != st.startTicks:
+				if err := r.exit(pid, nil); err != nil {
+					return err
+				}
+			}
+			if err := r.exec(pid); err != nil {
+				return err
+			}
+		}
+		for pid := range seen {
+			if _, ok := current[pid]; !ok {
+				if err := r.exit(pid, nil); err != nil {
+					return err
+				}
+			}
+		}
+		seen = current
+		r.prune()
+	}
+}
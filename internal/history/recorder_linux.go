@@ -0,0 +1,288 @@
+//go:build linux
+
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Options configure Record
+type Options struct {
+	// Poll /proc instead of using the proc connector
+	Poll bool
+	// Polling interval; processes living shorter than this are missed
+	Interval time.Duration
+	// Status messages (which event source is used, lost events)
+	Logf func(format string, args ...any)
+}
+
+// exitedRetention is how long exited processes stay in the process table,
+// so that children exec'ing after their parent exited (daemons) still get
+// the full ancestry
+const exitedRetention = time.Minute
+
+// tableEntry is what the recorder knows about a live (or just exited) process
+type tableEntry struct {
+	ppid    int
+	command string
+	cmdline string
+	// Forked but not exec'd yet: a copy of its parent, not worth recording
+	forkOnly bool
+	exited   time.Time
+}
+
+type recorder struct {
+	store *Store
+	procs map[int]*tableEntry
+	users map[int]string
+}
+
+// Record writes execs and exits to store until ctx is cancelled. Events
+// come from the netlink proc connector, which needs CAP_NET_ADMIN; without
+// it (or with opts.Poll) /proc is polled every opts.Interval instead.
+func Record(ctx context.Context, store *Store, opts Options) error {
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...any) {}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 100 * time.Millisecond
+	}
+	r := &recorder{store: store, procs: make(map[int]*tableEntry), users: readPasswd()}
+	r.scan()
+
+	if !opts.Poll {
+		conn, seq, err := openConnector()
+		if err == nil {
+			opts.Logf("recording from the proc connector")
+			return r.listen(ctx, conn, seq, opts)
+		}
+		opts.Logf("proc connector unavailable (%v), polling /proc every %s", err, opts.Interval)
+	} else {
+		opts.Logf("polling /proc every %s", opts.Interval)
+	}
+	return r.poll(ctx, opts.Interval)
+}
+
+// scan fills the process table with the processes already running
+func (r *recorder) scan() {
+	for _, pid := range listPIDs() {
+		if st, ok := readStat(pid); ok {
+			r.procs[pid] = &tableEntry{ppid: st.ppid, command: st.comm, cmdline: readCmdline(pid)}
+		}
+	}
+}
+
+// fork records a new child as a copy of its parent until it execs
+func (r *recorder) fork(parent, child int) {
+	e := &tableEntry{ppid: parent, forkOnly: true}
+	if p := r.procs[parent]; p != nil {
+		e.command, e.cmdline = p.command, p.cmdline
+	}
+	r.procs[child] = e
+}
+
+// exec records a process starting a new program, with its ancestry
+func (r *recorder) exec(pid int) error {
+	ev := model.HistoryEvent{Time: time.Now(), Event: model.HistoryExec, PID: pid}
+	e := r.procs[pid]
+	st, ok := readStat(pid)
+	switch {
+	case e != nil && e.forkOnly:
+		// The fork event named the real parent, even if it exited since
+		ev.PPID = e.ppid
+	case ok:
+		ev.PPID = st.ppid
+	case e != nil:
+		ev.PPID = e.ppid
+	}
+	if ok {
+		ev.Command = st.comm
+	}
+	ev.Cmdline = readCmdline(pid)
+	ev.Exe, _ = os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	ev.WorkingDir, _ = os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+	if uid, ok := readUID(pid); ok {
+		ev.User = r.userName(uid)
+	}
+	if ev.Command == "" && ev.Cmdline == "" {
+		// Gone before it could be read
+		if e != nil {
+			ev.Command = e.command
+		}
+		if ev.Command == "" {
+			return nil
+		}
+	}
+	ev.Ancestry = r.ancestry(ev.PPID)
+
+	r.procs[pid] = &tableEntry{ppid: ev.PPID, command: ev.Command, cmdline: ev.Cmdline}
+	return r.store.Append(ev)
+}
+
+// exit records a process ending. status is the raw wait status, nil when
+// unknown (polling).
+func (r *recorder) exit(pid int, status *int) error {
+	e := r.procs[pid]
+	if e != nil {
+		if !e.exited.IsZero() {
+			return nil
+		}
+		e.exited = time.Now()
+	}
+	if e != nil && e.forkOnly {
+		return nil
+	}
+
+	ev := model.HistoryEvent{Time: time.Now(), Event: model.HistoryExit, PID: pid}
+	if e != nil {
+		ev.PPID, ev.Command, ev.Cmdline = e.ppid, e.command, e.cmdline
+	} else if st, ok := readStat(pid); ok {
+		ev.PPID, ev.Command = st.ppid, st.comm
+	}
+	if status != nil {
+		if sig := *status & 0x7f; sig != 0 {
+			ev.Signal = sig
+			ev.CoreDumped = *status&0x80 != 0
+		} else {
+			code := (*status >> 8) & 0xff
+			ev.ExitCode = &code
+		}
+	}
+	return r.store.Append(ev)
+}
+
+// ancestry walks the process table up from ppid, init first
+func (r *recorder) ancestry(ppid int) []model.HistoryProcess {
+	var chain []model.HistoryProcess
+	seen := make(map[int]bool)
+	for pid := ppid; pid > 0 && !seen[pid] && len(chain) < 64; {
+		seen[pid] = true
+		e := r.procs[pid]
+		if e == nil {
+			st, ok := readStat(pid)
+			if !ok {
+				break
+			}
+			e = &tableEntry{ppid: st.ppid, command: st.comm, cmdline: readCmdline(pid)}
+			r.procs[pid] = e
+		}
+		chain = append(chain, model.HistoryProcess{PID: pid, Command: e.command, Cmdline: e.cmdline})
+		pid = e.ppid
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// prune forgets processes that exited more than exitedRetention ago
+func (r *recorder) prune() {
+	cutoff := time.Now().Add(-exitedRetention)
+	for pid, e := range r.procs {
+		if !e.exited.IsZero() && e.exited.Before(cutoff) {
+			delete(r.procs, pid)
+		}
+	}
+}
+
+func (r *recorder) userName(uid int) string {
+	if name, ok := r.users[uid]; ok {
+		return name
+	}
+	return strconv.Itoa(uid)
+}
+
+// procStat is the part of /proc/<pid>/stat the recorder uses
+type procStat struct {
+	comm       string
+	ppid       int
+	startTicks string
+}
+
+func readStat(pid int) (procStat, bool) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return procStat{}, false
+	}
+	return parseStat(string(data))
+}
+
+// parseStat reads the command (in parentheses, may contain spaces and
+// parentheses), the parent PID and the start time of a stat line
+func parseStat(raw string) (procStat, bool) {
+	open, end := strings.Index(raw, "("), strings.LastIndex(raw, ")")
+	if open == -1 || end < open || end+2 > len(raw) {
+		return procStat{}, false
+	}
+	fields := strings.Fields(raw[end+2:])
+	if len(fields) < 20 {
+		return procStat{}, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return procStat{}, false
+	}
+	return procStat{comm: raw[open+1 : end], ppid: ppid, startTicks: fields[19]}, true
+}
+
+func readCmdline(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+}
+
+func readUID(pid int) (int, bool) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "Uid:"); ok {
+			if fields := strings.Fields(v); len(fields) > 0 {
+				uid, err := strconv.Atoi(fields[0])
+				return uid, err == nil
+			}
+		}
+	}
+	return 0, false
+}
+
+func readPasswd() map[int]string {
+	users := map[int]string{0: "root"}
+	data, err := os.ReadFile("/etc/passwd")
+	if err != nil {
+		return users
+	}
+	for line := range strings.Lines(string(data)) {
+		fields := strings.Split(line, ":")
+		if len(fields) > 2 {
+			if uid, err := strconv.Atoi(fields[2]); err == nil {
+				users[uid] = fields[0]
+			}
+		}
+	}
+	return users
+}
+
+func listPIDs() []int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, e := range entries {
+		if pid, err := strconv.Atoi(e.Name()); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
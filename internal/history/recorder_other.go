@@ -0,0 +1,21 @@
+//go:build !linux
+
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Options configure Record
+type Options struct {
+	Poll     bool
+	Interval time.Duration
+	Logf     func(format string, args ...any)
+}
+
+// Record is only implemented on Linux
+func Record(ctx context.Context, store *Store, opts Options) error {
+	return fmt.Errorf("witr record is only supported on Linux")
+}
@@ -0,0 +1,40 @@
+package history
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// Runs groups the events of pid into the processes that had it, oldest
+// first. An exit ends a run; execs before it (a shell exec'ing its command)
+// belong to the same run.
+func Runs(events []model.HistoryEvent, pid int) []model.HistoryRun {
+	var runs []model.HistoryRun
+	var cur *model.HistoryRun
+	for _, ev := range events {
+		if ev.PID != pid {
+			continue
+		}
+		if cur == nil {
+			runs = append(runs, model.HistoryRun{})
+			cur = &runs[len(runs)-1]
+		}
+		switch ev.Event {
+		case model.HistoryExec:
+			cur.Execs = append(cur.Execs, ev)
+		case model.HistoryExit:
+			cur.Exit = &ev
+			cur = nil
+		}
+	}
+	return runs
+}
+
+// LastExec returns the most recent exec event of a process whose command
+// or command line matches name, for looking up processes by name
+func LastExec(events []model.HistoryEvent, name string) (model.HistoryEvent, bool) {
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if ev.Event == model.HistoryExec && (ev.Command == name || ev.Cmdline == name) {
+			return ev, true
+		}
+	}
+	return model.HistoryEvent{}, false
+}
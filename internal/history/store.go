@@ -0,0 +1,249 @@
+// Package history keeps a bounded on-disk log of process execs and exits so
+// that processes can be explained after they are gone.
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// The history file is a ring buffer of fixed-size slots. Slot 0 holds a
+// header, every other slot one JSON encoded event padded with spaces and
+// ending in a newline; slots not written yet are zero. Event n goes to slot
+// 1 + n%slots; the oldest events are overwritten once the ring is full.
+const (
+	slotSize    = 2048
+	headerMagic = "witr-history/1"
+
+	// DefaultSlots bounds the file at 16 MiB
+	DefaultSlots = 8192
+)
+
+type header struct {
+	Magic    string `json:"magic"`
+	SlotSize int    `json:"slot_size"`
+	Slots    int    `json:"slots"`
+}
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("locked")
+
+// SystemPath is where a recorder running as root keeps its history
+const SystemPath = "/var/lib/witr/history"
+
+// DefaultPath returns the history file of the current user: SystemPath for
+// root, else $XDG_STATE_HOME/witr/history (~/.local/state/witr/history)
+func DefaultPath() string {
+	if os.Geteuid() == 0 {
+		return SystemPath
+	}
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return SystemPath
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "witr", "history")
+}
+
+// Store appends events to a history file
+type Store struct {
+	f     *os.File
+	slots int
+	next  uint64
+}
+
+// Open opens or creates the history file at path with room for slots
+// events. Events already in the file are kept; if it was created with a
+// different size, the newest ones are carried over. The file stays locked
+// until Close, Open fails while another process has it open.
+func Open(path string, slots int) (*Store, error) {
+	if slots <= 0 {
+		slots = DefaultSlots
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, errLocked) {
+			return nil, fmt.Errorf("%s is already in use by another witr record; stop it or pass a different --file", path)
+		}
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+
+	existing, hdr, err := readFile(f)
+	if err != nil {
+		// Only a new (empty) file may be initialized, never overwrite
+		// something that is not a history file
+		if info, statErr := f.Stat(); statErr != nil || info.Size() > 0 {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err != nil || hdr.Slots != slots {
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return nil, err
+		}
+		if err := writeHeader(f, slots); err != nil {
+			f.Close()
+			return nil, err
+		}
+		s := &Store{f: f, slots: slots}
+		if len(existing) > slots {
+			existing = existing[len(existing)-slots:]
+		}
+		for _, ev := range existing {
+			if err := s.Append(ev); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		return s, nil
+	}
+
+	s := &Store{f: f, slots: slots}
+	if len(existing) > 0 {
+		s.next = existing[len(existing)-1].Seq + 1
+	}
+	return s, nil
+}
+
+// Append writes an event to the next slot, setting its Seq. Events too
+// large for a slot lose command lines and then distant ancestors.
+func (s *Store) Append(ev model.HistoryEvent) error {
+	ev.Seq = s.next
+	data, err := encodeSlot(ev)
+	if err != nil {
+		return err
+	}
+	off := int64(1+s.next%uint64(s.slots)) * slotSize
+	if _, err := s.f.WriteAt(data, off); err != nil {
+		return err
+	}
+	s.next++
+	return nil
+}
+
+// Close closes the history file, releasing its lock
+func (s *Store) Close() error {
+	return s.f.Close()
+}
+
+// ReadEvents returns the events in the history file at path, oldest first
+func ReadEvents(path string) ([]model.HistoryEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events, _, err := readFile(f)
+	return events, err
+}
+
+func writeHeader(f *os.File, slots int) error {
+	data, err := json.Marshal(header{Magic: headerMagic, SlotSize: slotSize, Slots: slots})
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(pad(data), 0); err != nil {
+		return err
+	}
+	// Reserve the whole ring up front (sparse where supported)
+	return f.Truncate(int64(1+slots) * slotSize)
+}
+
+func readFile(f *os.File) ([]model.HistoryEvent, header, error) {
+	var hdr header
+	buf := make([]byte, slotSize)
+	if _, err := f.ReadAt(buf, 0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, hdr, fmt.Errorf("not a witr history file")
+		}
+		return nil, hdr, err
+	}
+	if err := json.Unmarshal(bytes.TrimRight(buf, " \n\x00"), &hdr); err != nil || hdr.Magic != headerMagic || hdr.SlotSize != slotSize {
+		return nil, hdr, fmt.Errorf("not a witr history file")
+	}
+
+	var events []model.HistoryEvent
+	for i := 1; i <= hdr.Slots; i++ {
+		if _, err := f.ReadAt(buf, int64(i)*slotSize); err != nil {
+			break
+		}
+		data := bytes.TrimRight(buf, " \n\x00")
+		if len(data) == 0 {
+			continue
+		}
+		// A slot torn by a crash mid-write fails to decode and is skipped
+		var ev model.HistoryEvent
+		if json.Unmarshal(data, &ev) == nil {
+			events = append(events, ev)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+	return events, hdr, nil
+}
+
+// encodeSlot encodes an event into exactly one slot
+func encodeSlot(ev model.HistoryEvent) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < slotSize {
+			return pad(data), nil
+		}
+		switch {
+		case attempt == 0:
+			ev.Cmdline = truncate(ev.Cmdline, 512)
+			ev.Ancestry = append([]model.HistoryProcess(nil), ev.Ancestry...)
+			for i := range ev.Ancestry {
+				ev.Ancestry[i].Cmdline = truncate(ev.Ancestry[i].Cmdline, 128)
+			}
+		case attempt == 1:
+			for i := range ev.Ancestry {
+				ev.Ancestry[i].Cmdline = ""
+			}
+		case len(ev.Ancestry) > 0:
+			ev.Ancestry = ev.Ancestry[1:]
+		default:
+			ev.Cmdline = truncate(ev.Cmdline, 128)
+			ev.WorkingDir = truncate(ev.WorkingDir, 128)
+			ev.Exe = truncate(ev.Exe, 128)
+			if attempt > 64 {
+				return nil, fmt.Errorf("history event for pid %d does not fit a slot", ev.PID)
+			}
+		}
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+func pad(data []byte) []byte {
+	slot := bytes.Repeat([]byte{' '}, slotSize)
+	copy(slot, data)
+	slot[slotSize-1] = '\n'
+	return slot
+}
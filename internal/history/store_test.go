@@ -0,0 +1,154 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestStoreRing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	s, err := Open(path, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pid := 1; pid <= 6; pid++ {
+		if err := s.Append(model.HistoryEvent{Time: time.Now(), Event: model.HistoryExec, PID: pid}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	events, err := ReadEvents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pids(events); got != "3 4 5 6" {
+		t.Errorf("after wrapping got pids %q, want %q", got, "3 4 5 6")
+	}
+
+	// Reopening continues after the newest event
+	s, err = Open(path, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Append(model.HistoryEvent{Event: model.HistoryExec, PID: 7})
+	s.Close()
+	events, _ = ReadEvents(path)
+	if got := pids(events); got != "4 5 6 7" {
+		t.Errorf("after reopening got pids %q, want %q", got, "4 5 6 7")
+	}
+
+	// Resizing keeps the newest events
+	s, err = Open(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	events, _ = ReadEvents(path)
+	if got := pids(events); got != "6 7" {
+		t.Errorf("after shrinking got pids %q, want %q", got, "6 7")
+	}
+}
+
+func TestStoreOversizedEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	s, err := Open(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := model.HistoryEvent{Event: model.HistoryExec, PID: 42, Command: "java", Cmdline: strings.Repeat("x", 5000)}
+	for i := 1; i <= 30; i++ {
+		ev.Ancestry = append(ev.Ancestry, model.HistoryProcess{PID: i, Command: "sh", Cmdline: strings.Repeat("y", 300)})
+	}
+	if err := s.Append(ev); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	events, err := ReadEvents(path)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ReadEvents() = %d events, %v", len(events), err)
+	}
+	got := events[0]
+	if got.PID != 42 || !strings.HasPrefix(got.Cmdline, "xxx") || len(got.Ancestry) == 0 {
+		t.Errorf("oversized event not kept: %+v", got)
+	}
+	if last := got.Ancestry[len(got.Ancestry)-1]; last.PID != 30 {
+		t.Errorf("nearest ancestor dropped, last is pid %d", last.PID)
+	}
+}
+
+func TestOpenRefusesOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	os.WriteFile(path, []byte("not a history file"), 0o600)
+	if _, err := Open(path, 4); err == nil {
+		t.Fatal("Open() of a non-history file succeeded")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "not a history file" {
+		t.Errorf("file was modified: %q", data)
+	}
+}
+
+func TestOpenLocks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("history files are not locked on windows")
+	}
+	path := filepath.Join(t.TempDir(), "history")
+	s, err := Open(path, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, 4); err == nil || !strings.Contains(err.Error(), "already in use by another witr record") {
+		t.Fatalf("second Open() error = %v, want the file to be in use", err)
+	}
+	// Readers are not locked out
+	if _, err := ReadEvents(path); err != nil {
+		t.Errorf("ReadEvents() while recording: %v", err)
+	}
+	s.Close()
+	s, err = Open(path, 4)
+	if err != nil {
+		t.Fatalf("Open() after Close: %v", err)
+	}
+	s.Close()
+}
+
+func TestRuns(t *testing.T) {
+	code := 1
+	events := []model.HistoryEvent{
+		{Event: model.HistoryExit, PID: 10, Command: "old"},
+		{Event: model.HistoryExec, PID: 10, Command: "sh"},
+		{Event: model.HistoryExec, PID: 11, Command: "other"},
+		{Event: model.HistoryExec, PID: 10, Command: "curl"},
+		{Event: model.HistoryExit, PID: 10, Command: "curl", ExitCode: &code},
+		{Event: model.HistoryExec, PID: 10, Command: "make"},
+	}
+	runs := Runs(events, 10)
+	if len(runs) != 3 {
+		t.Fatalf("Runs() = %d runs, want 3", len(runs))
+	}
+	if len(runs[0].Execs) != 0 || runs[0].Exit == nil || runs[0].Exit.Command != "old" {
+		t.Errorf("run 0 = %+v, want only the exit of old", runs[0])
+	}
+	if len(runs[1].Execs) != 2 || runs[1].Execs[1].Command != "curl" || runs[1].Exit == nil || *runs[1].Exit.ExitCode != 1 {
+		t.Errorf("run 1 = %+v, want sh then curl exiting 1", runs[1])
+	}
+	if len(runs[2].Execs) != 1 || runs[2].Exit != nil {
+		t.Errorf("run 2 = %+v, want make without exit", runs[2])
+	}
+}
+
+func pids(events []model.HistoryEvent) string {
+	var s []string
+	for _, ev := range events {
+		s = append(s, strconv.Itoa(ev.PID))
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// StaleSkippedNote tells that processes could not be checked for deleted
+// files, normally those of other users when not running as root
+func StaleSkippedNote(n int) string {
+	if n == 1 {
+		return "1 process could not be inspected (permission denied or timed out); run as root to check every process"
+	}
+	return fmt.Sprintf("%d processes could not be inspected (permission denied or timed out); run as root to check every process", n)
+}
+
+// RenderStaleAudit prints processes running deleted binaries or libraries,
+// followed by the systemd units that would need a restart. skipped counts
+// the processes that could not be inspected.
+func RenderStaleAudit(w io.Writer, stale []model.StaleProcess, skipped int, colorEnabled bool) {
+	out := NewPrinter(w)
+	if len(stale) == 0 && skipped > 0 {
+		if colorEnabled {
+			out.Printf("%sNo stale processes among those that could be inspected.%s\n", colorDimYellow, colorReset)
+		} else {
+			out.Println("No stale processes among those that could be inspected.")
+		}
+		out.Printf("Note: %s\n", StaleSkippedNote(skipped))
+		return
+	}
+	if len(stale) == 0 {
+		if colorEnabled {
+			out.Printf("%sNo processes are running deleted binaries or libraries.%s\n", colorGreen, colorReset)
+		} else {
+			out.Println("No processes are running deleted binaries or libraries.")
+		}
+		return
+	}
+
+	if colorEnabled {
+		out.Printf("%s%-8s %-10s %-16s %-24s %s%s\n", colorMagenta, "PID", "USER", "COMMAND", "UNIT", "STALE", colorReset)
+	} else {
+		out.Printf("%-8s %-10s %-16s %-24s %s\n", "PID", "USER", "COMMAND", "UNIT", "STALE")
+	}
+
+	// System units, then the units of each user manager, which the system
+	// manager does not know
+	var units, users []string
+	userUnits := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, p := range stale {
+		unit := p.Unit
+		switch {
+		case unit == "":
+			unit = "-"
+		case p.UnitUser != "":
+			if key := p.UnitUser + "/" + unit; !seen[key] {
+				seen[key] = true
+				if userUnits[p.UnitUser] == nil {
+					users = append(users, p.UnitUser)
+				}
+				userUnits[p.UnitUser] = append(userUnits[p.UnitUser], unit)
+			}
+			unit += " (" + p.UnitUser + ")"
+		case !seen[unit]:
+			seen[unit] = true
+			units = append(units, unit)
+		}
+
+		var what []string
+		if p.ExeDeleted {
+			what = append(what, "exe "+withOwner(p.Exe, p.DeletedOwners))
+		}
+		switch len(p.DeletedLibs) {
+		case 0:
+		case 1:
+			what = append(what, "lib "+withOwner(p.DeletedLibs[0], p.DeletedOwners))
+		default:
+			what = append(what, fmt.Sprintf("%d libs (%s, ...)", len(p.DeletedLibs), withOwner(p.DeletedLibs[0], p.DeletedOwners)))
+		}
+
+		out.Printf("%-8d %-10s %-16s %-24s %s\n", p.PID, p.User, p.Command, unit, strings.Join(what, ", "))
+	}
+
+	out.Printf("\n%d process(es) need a restart to pick up upgraded files.\n", len(stale))
+	if len(units) > 0 {
+		out.Printf("Restart with: systemctl restart %s\n", strings.Join(units, " "))
+	}
+	for _, user := range users {
+		out.Printf("Restart with: systemctl --user -M %s@ restart %s\n", user, strings.Join(userUnits[user], " "))
+	}
+	if skipped > 0 {
+		out.Printf("Note: %s\n", StaleSkippedNote(skipped))
+	}
+}
+
+// withOwner appends the package now owning a deleted path, if known
+func withOwner(path string, owners map[string]string) string {
+	if owner := owners[path]; owner != "" {
+		return path + " [" + owner + "]"
+	}
+	return path
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderStaleAuditSkipped(t *testing.T) {
+	var buf bytes.Buffer
+	RenderStaleAudit(&buf, nil, 3, false)
+	got := buf.String()
+	if strings.Contains(got, "No processes are running deleted binaries") {
+		t.Errorf("all-clear printed with skipped processes:\n%s", got)
+	}
+	if !strings.Contains(got, "3 processes could not be inspected") {
+		t.Errorf("skipped processes not reported:\n%s", got)
+	}
+
+	buf.Reset()
+	RenderStaleAudit(&buf, []model.StaleProcess{{PID: 10, Command: "nginx", User: "root", Exe: "/usr/sbin/nginx", ExeDeleted: true}}, 1, false)
+	if !strings.Contains(buf.String(), "Note: 1 process could not be inspected") {
+		t.Errorf("skipped process not reported after the list:\n%s", buf.String())
+	}
+}
+
+func TestRenderStaleAuditUnits(t *testing.T) {
+	var buf bytes.Buffer
+	RenderStaleAudit(&buf, []model.StaleProcess{
+		{PID: 10, Command: "nginx", User: "root", Unit: "nginx.service", Exe: "/usr/sbin/nginx", ExeDeleted: true},
+		{PID: 20, Command: "syncthing", User: "alice", Unit: "syncthing.service", UnitUser: "alice", Exe: "/usr/bin/syncthing", ExeDeleted: true},
+		{PID: 21, Command: "systemd", User: "alice", Exe: "/usr/lib/systemd/systemd", ExeDeleted: true},
+	}, 0, false)
+	got := buf.String()
+	for _, want := range []string{
+		"Restart with: systemctl restart nginx.service\n",
+		"Restart with: systemctl --user -M alice@ restart syncthing.service\n",
+		"syncthing.service (alice)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "user@") || strings.Count(got, "Restart with") != 2 {
+		t.Errorf("user units mixed into the system restart:\n%s", got)
+	}
+}
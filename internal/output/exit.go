@@ -0,0 +1,144 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// formatUnitExit describes how the main process of a unit last ended
+func formatUnitExit(u model.UnitState) string {
+	switch u.ExecMainCode {
+	case "exited":
+		return fmt.Sprintf("exit code %d", u.ExecMainStatus)
+	case "killed":
+		return "killed by " + model.SignalName(u.ExecMainStatus)
+	case "dumped":
+		return "crashed with " + model.SignalName(u.ExecMainStatus) + ", core dumped"
+	}
+	return ""
+}
+
+// formatOOMKill describes one OOM kill, e.g.
+// "java (pid 1234), anon-rss 480.0 MB, memory cgroup /system.slice/app.service"
+func formatOOMKill(k model.OOMKill) string {
+	text := fmt.Sprintf("%s (pid %d)", k.Command, k.PID)
+	if k.AnonRSSKB > 0 {
+		text += ", anon-rss " + formatSize(k.AnonRSSKB*1024)
+	}
+	if k.MemCgroup != "" && k.MemCgroup != "/" {
+		text += ", memory cgroup " + k.MemCgroup
+	} else {
+		text += ", system-wide"
+	}
+	return text
+}
+
+// RenderExitReport explains how a process or unit stopped: the conclusion
+// first, then what each source (recorded history, systemd, kernel log) says
+func RenderExitReport(w io.Writer, r model.ExitReport, colorEnabled bool) {
+	out := NewPrinter(w)
+	label := func(name string, color ansiString) ansiString {
+		return fieldLabel(name, color, colorEnabled)
+	}
+
+	target := r.Target
+	if r.Command != "" && r.Unit == nil {
+		target += " (" + r.Command + ")"
+	}
+	out.Printf("%s%s\n", label("Target", colorBlue), target)
+	if colorEnabled {
+		out.Printf("%s%s%s%s\n", label("Reason", colorRed), colorRed, r.Reason, colorReset)
+	} else {
+		out.Printf("%s%s\n", label("Reason", colorRed), r.Reason)
+	}
+	if r.Running {
+		if r.Unit != nil {
+			out.Printf("%sthe unit is active again\n", label("Now", colorGreen))
+		} else {
+			out.Printf("%sa process with this PID is running (witr --pid %d)\n", label("Now", colorGreen), r.PID)
+		}
+	}
+
+	if run := r.Run; run != nil {
+		if colorEnabled {
+			out.Printf("\n%sRecorded History%s :\n", colorMagenta, colorReset)
+		} else {
+			out.Printf("\nRecorded History :\n")
+		}
+		if len(run.Execs) > 0 {
+			first, last := run.Execs[0], run.Execs[len(run.Execs)-1]
+			out.Printf("%s%s (pid %d)\n", label("Process", colorBlue), last.Command, last.PID)
+			if last.Cmdline != "" {
+				out.Printf("%s%s\n", label("Command", colorGreen), last.Cmdline)
+			}
+			out.Printf("%s%s (%s)\n", label("Started", colorMagenta), formatAgo(time.Since(first.Time)), first.Time.Format(historyTime))
+			if len(first.Ancestry) > 0 {
+				parent := first.Ancestry[len(first.Ancestry)-1]
+				out.Printf("%s%s (pid %d)\n", label("Spawned by", colorMagenta), parent.Command, parent.PID)
+			}
+			if run.Exit != nil {
+				out.Printf("%s%s after %s (%s)\n", label("Exited", colorRed), formatExit(*run.Exit), formatRuntime(run.Exit.Time.Sub(first.Time)), run.Exit.Time.Format(historyTime))
+			} else {
+				out.Printf("%snot recorded (the recorder was stopped or missed it)\n", label("Exited", colorRed))
+			}
+		} else if run.Exit != nil {
+			out.Printf("%s%s (pid %d)\n", label("Process", colorBlue), run.Exit.Command, run.Exit.PID)
+			out.Printf("%s%s (%s)\n", label("Exited", colorRed), formatExit(*run.Exit), run.Exit.Time.Format(historyTime))
+		}
+	}
+
+	if u := r.Unit; u != nil {
+		if colorEnabled {
+			out.Printf("\n%sSystemd%s :\n", colorMagenta, colorReset)
+		} else {
+			out.Printf("\nSystemd :\n")
+		}
+		state := u.ActiveState + "/" + u.SubState
+		if u.Result != "" {
+			state += ", result " + u.Result
+		}
+		if u.FromJournal {
+			state += ", from the journal, systemd no longer knows the unit"
+		}
+		out.Printf("%s%s (%s)\n", label("Unit", colorBlue), u.Name, state)
+		if !u.StateChange.IsZero() {
+			out.Printf("%s%s (%s)\n", label("Last Change", colorMagenta), formatAgo(time.Since(u.StateChange)), u.StateChange.Format("Mon 2006-01-02 15:04:05 -07:00"))
+		}
+		if u.ExecMainPID > 0 {
+			text := fmt.Sprintf("pid %d", u.ExecMainPID)
+			if exit := formatUnitExit(*u); exit != "" {
+				text += ", " + exit
+			}
+			if !u.ExecMainExit.IsZero() {
+				text += " at " + u.ExecMainExit.Format("15:04:05")
+				if !u.ExecMainStart.IsZero() {
+					text += " after " + formatRuntime(u.ExecMainExit.Sub(u.ExecMainStart))
+				}
+			} else if u.MainPID == u.ExecMainPID {
+				text += ", running"
+			}
+			out.Printf("%s%s\n", label("Main PID", colorGreen), text)
+		}
+		if u.NRestarts > 0 {
+			out.Printf("%s%d\n", label("Restarts", colorDimYellow), u.NRestarts)
+		}
+	}
+
+	if len(r.OOMKills) > 0 {
+		if colorEnabled {
+			out.Printf("\n%sOOM Kills%s :\n", colorMagenta, colorReset)
+		} else {
+			out.Printf("\nOOM Kills :\n")
+		}
+		for _, k := range r.OOMKills {
+			when := "unknown time"
+			if !k.Time.IsZero() {
+				when = k.Time.Format("Mon 2006-01-02 15:04:05")
+			}
+			out.Printf("  %s  %s (%s)\n", when, formatOOMKill(k), k.Source)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fdCategoryNames are the singular and plural display names of each fd category
+var fdCategoryNames = map[model.FDCategory][2]string{
+	model.FDFile:   {"file", "files"},
+	model.FDDir:    {"directory", "directories"},
+	model.FDPipe:   {"pipe", "pipes"},
+	model.FDSocket: {"socket", "sockets"},
+	model.FDAnon:   {"anon inode", "anon inodes"},
+	model.FDDevice: {"device", "devices"},
+	model.FDOther:  {"other", "other"},
+}
+
+func fdCategoryName(c model.FDCategory, n int) string {
+	if n == 1 {
+		return fdCategoryNames[c][0]
+	}
+	return fdCategoryNames[c][1]
+}
+
+// formatFDCounts summarizes per-category counts, e.g. "3 files, 2 sockets, 1 pipe"
+func formatFDCounts(counts map[model.FDCategory]int) string {
+	var parts []string
+	for _, c := range model.FDCategories {
+		if n := counts[c]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, fdCategoryName(c, n)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatFDPeers lists the processes on the other side of a pipe
+func formatFDPeers(peers []model.FDPeer) string {
+	names := make([]string, 0, len(peers))
+	for _, p := range peers {
+		names = append(names, fmt.Sprintf("%s (pid %d)", p.Command, p.PID))
+	}
+	return strings.Join(names, ", ")
+}
+
+// RenderFDs prints the open file descriptors of the target grouped by category
+func RenderFDs(w io.Writer, r model.Result, colorEnabled bool) {
+	out := NewPrinter(w)
+	proc := r.Process
+
+	if colorEnabled {
+		out.Printf("%sTarget%s      : %s%s%s (%spid %d%s)\n", colorBlue, colorReset, colorGreen, proc.Command, colorReset, colorBold, proc.PID, colorReset)
+	} else {
+		out.Printf("Target      : %s (pid %d)\n", proc.Command, proc.PID)
+	}
+
+	limit := "unlimited"
+	if proc.FDLimit > 0 {
+		limit = fmt.Sprintf("%d", proc.FDLimit)
+	}
+	if colorEnabled {
+		out.Printf("%sDescriptors%s : %d/%s", colorGreen, colorReset, proc.FDCount, limit)
+	} else {
+		out.Printf("Descriptors : %d/%s", proc.FDCount, limit)
+	}
+	if summary := formatFDCounts(proc.FDCounts); summary != "" {
+		out.Printf(" (%s)", summary)
+	}
+	out.Println()
+
+	if len(proc.FDs) == 0 {
+		// No classification available on this platform, fall back to raw targets
+		for _, fd := range proc.FileDescs {
+			out.Printf("  %s\n", fd)
+		}
+		return
+	}
+
+	for _, c := range model.FDCategories {
+		n := proc.FDCounts[c]
+		if n == 0 {
+			continue
+		}
+		plural := fdCategoryNames[c][1]
+		title := strings.ToUpper(plural[:1]) + plural[1:]
+		if colorEnabled {
+			out.Printf("\n%s%s%s (%d):\n", colorMagenta, title, colorReset, n)
+		} else {
+			out.Printf("\n%s (%d):\n", title, n)
+		}
+		for _, fd := range proc.FDs {
+			if fd.Category != c {
+				continue
+			}
+			line := fd.Target
+			switch {
+			case (fd.Category == model.FDSocket || fd.Category == model.FDAnon) && fd.Detail != "":
+				line = fd.Detail
+			case fd.Detail != "":
+				line += " " + fd.Detail
+			}
+			if len(fd.Peers) > 0 {
+				line += ", peer: " + formatFDPeers(fd.Peers)
+			}
+			if colorEnabled {
+				out.Printf("  %s%-4d%s %s\n", colorBold, fd.FD, colorReset, line)
+			} else {
+				out.Printf("  %-4d %s\n", fd.FD, line)
+			}
+		}
+	}
+}
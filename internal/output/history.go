@@ -0,0 +1,150 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// historyTime is the timestamp layout of recorded events
+const historyTime = "Mon 2006-01-02 15:04:05.000 -07:00"
+
+// formatExit describes how a process ended, e.g. "killed by SIGKILL (9)"
+func formatExit(ev model.HistoryEvent) string {
+	switch {
+	case ev.Signal != 0 && ev.CoreDumped:
+		return "killed by " + model.SignalName(ev.Signal) + ", core dumped"
+	case ev.Signal != 0:
+		return "killed by " + model.SignalName(ev.Signal)
+	case ev.ExitCode != nil:
+		return fmt.Sprintf("exit code %d", *ev.ExitCode)
+	default:
+		return "exited, status not recorded"
+	}
+}
+
+// formatRuntime renders a process lifetime with a precision that suits it
+func formatRuntime(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// fieldLabel renders "Name        : ", colored when enabled
+func fieldLabel(name string, color ansiString, colorEnabled bool) ansiString {
+	pad := ansiString(strings.Repeat(" ", max(12-len(name), 0)) + ": ")
+	if colorEnabled {
+		return color + ansiString(name) + colorReset + pad
+	}
+	return ansiString(name) + pad
+}
+
+// RenderHistory explains a PID from recorded exec and exit events: for
+// each process that had it, what it ran, who spawned it and how it ended
+func RenderHistory(w io.Writer, h model.ProcessHistory, colorEnabled bool) {
+	out := NewPrinter(w)
+	label := func(name string, color ansiString) ansiString {
+		return fieldLabel(name, color, colorEnabled)
+	}
+
+	state := "not running"
+	if h.Running {
+		state = "running now"
+	}
+	if colorEnabled {
+		out.Printf("%sTarget%s      : pid %d (%s, from recorded history)\n", colorBlue, colorReset, h.PID, state)
+	} else {
+		out.Printf("Target      : pid %d (%s, from recorded history)\n", h.PID, state)
+	}
+
+	for i, run := range h.Runs {
+		out.Println("")
+		if len(h.Runs) > 1 {
+			out.Printf("Run %d of %d\n", i+1, len(h.Runs))
+		}
+
+		if len(run.Execs) == 0 {
+			// Started before the recorder, only its exit was seen
+			ev := run.Exit
+			out.Printf("%s%s (pid %d)\n", label("Process", colorBlue), ev.Command, ev.PID)
+			if ev.Cmdline != "" {
+				out.Printf("%s%s\n", label("Command", colorGreen), ev.Cmdline)
+			}
+			out.Printf("%sbefore recording began\n", label("Started", colorMagenta))
+			out.Printf("%s%s (%s)\n", label("Exited", colorRed), formatExit(*ev), ev.Time.Format(historyTime))
+			continue
+		}
+
+		first, last := run.Execs[0], run.Execs[len(run.Execs)-1]
+		out.Printf("%s%s (pid %d)\n", label("Process", colorBlue), last.Command, last.PID)
+		if last.User != "" {
+			out.Printf("%s%s\n", label("User", colorCyan), last.User)
+		}
+		if last.Cmdline != "" {
+			out.Printf("%s%s\n", label("Command", colorGreen), last.Cmdline)
+		}
+		if last.Exe != "" {
+			out.Printf("%s%s\n", label("Executable", colorGreen), last.Exe)
+		}
+		if last.WorkingDir != "" {
+			out.Printf("%s%s\n", label("Working Dir", colorGreen), last.WorkingDir)
+		}
+		if len(run.Execs) > 1 {
+			var chain []string
+			for _, ev := range run.Execs {
+				chain = append(chain, ev.Command)
+			}
+			out.Printf("%s%s\n", label("Exec chain", colorGreen), strings.Join(chain, " → "))
+		}
+		out.Printf("%s%s (%s)\n", label("Started", colorMagenta), formatAgo(time.Since(first.Time)), first.Time.Format(historyTime))
+
+		switch {
+		case run.Exit != nil:
+			out.Printf("%s%s after %s (%s)\n", label("Exited", colorRed), formatExit(*run.Exit), formatRuntime(run.Exit.Time.Sub(first.Time)), run.Exit.Time.Format(historyTime))
+		case h.Running && i == len(h.Runs)-1:
+			out.Printf("%sstill running\n", label("Exited", colorRed))
+		default:
+			out.Printf("%snot recorded (the recorder was stopped or missed it)\n", label("Exited", colorRed))
+		}
+
+		if len(first.Ancestry) > 0 {
+			parent := first.Ancestry[len(first.Ancestry)-1]
+			out.Printf("%s%s (pid %d)\n", label("Spawned by", colorMagenta), parent.Command, parent.PID)
+			if parent.Cmdline != "" && parent.Cmdline != parent.Command {
+				out.Printf("              %s\n", parent.Cmdline)
+			}
+		} else if first.PPID > 0 {
+			out.Printf("%spid %d\n", label("Spawned by", colorMagenta), first.PPID)
+		}
+
+		if colorEnabled {
+			out.Printf("\n%sWhy It Existed%s :\n  ", colorMagenta, colorReset)
+		} else {
+			out.Printf("\nWhy It Existed :\n  ")
+		}
+		for _, p := range first.Ancestry {
+			if colorEnabled {
+				out.Printf("%s (%spid %d%s) %s→%s ", p.Command, colorBold, p.PID, colorReset, colorMagenta, colorReset)
+			} else {
+				out.Printf("%s (pid %d) → ", p.Command, p.PID)
+			}
+		}
+		if colorEnabled {
+			out.Printf("%s%s%s (%spid %d%s)\n", colorGreen, first.Command, colorReset, colorBold, first.PID, colorReset)
+		} else {
+			out.Printf("%s (pid %d)\n", first.Command, first.PID)
+		}
+	}
+
+	if h.Running {
+		out.Printf("\nA process with this PID is running now. For the live process run: witr --pid %d\n", h.PID)
+	}
+}
@@ -30,9 +30,9 @@ func RenderShort(w io.Writer, r model.Result, colorEnabled bool) {
 			if i == len(r.Ancestry)-1 {
 				nameColor = colorGreenShort
 			}
-			p.Printf("%s%s%s (%spid %d%s)", nameColor, proc.Command, colorResetShort, colorBoldShort, proc.PID, colorResetShort)
+			p.Printf("%s%s%s (%spid %d%s)", nameColor, processLabel(proc), colorResetShort, colorBoldShort, proc.PID, colorResetShort)
 		} else {
-			p.Printf("%s (pid %d)", proc.Command, proc.PID)
+			p.Printf("%s (pid %d)", processLabel(proc), proc.PID)
 		}
 	}
 	p.Println()
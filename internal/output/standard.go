@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -24,10 +27,26 @@ var (
 // formatDetailLabel formats a detail key into a padded label for display
 func formatDetailLabel(key string) string {
 	labels := map[string]string{
-		"type":      "              Type",
-		"plist":     "              Plist",
-		"triggers":  "              Trigger",
-		"keepalive": "              KeepAlive",
+		"type":        "              Type",
+		"plist":       "              Plist",
+		"triggers":    "              Trigger",
+		"keepalive":   "              KeepAlive",
+		"appserver":   "              App Server",
+		"role":        "              Role",
+		"master":      "              Master PID",
+		"worker":      "              Worker",
+		"pool":        "              Pool",
+		"app":         "              App",
+		"config":      "              Config",
+		"procfile":    "              Procfile",
+		"process":     "              Process",
+		"home":        "              PM2 Home",
+		"id":          "              ID",
+		"exec_mode":   "              Exec Mode",
+		"autorestart": "              Autorestart",
+		"watch":       "              Watch",
+		"out_log":     "              Stdout Log",
+		"err_log":     "              Stderr Log",
 	}
 	if label, ok := labels[key]; ok {
 		return label
@@ -35,8 +54,162 @@ func formatDetailLabel(key string) string {
 	return "              " + key
 }
 
-// RenderWarnings prints only the warnings, with color if enabled
-func RenderWarnings(w io.Writer, warnings []string, colorEnabled bool) {
+// sourceDetailKeys orders source details: known keys first, in a fixed
+// order, then any others alphabetically. The restart count is left out, it
+// has its own Restarts line.
+func sourceDetailKeys(details map[string]string) []string {
+	order := []string{"type", "plist", "triggers", "keepalive",
+		"name", "appserver", "role", "master", "worker", "pool", "process", "app", "id", "config", "procfile",
+		"state", "exec_mode", "autostart", "autorestart", "startretries", "watch", "home", "out_log", "err_log",
+		"file", "line", "schedule", "next_run", "user", "command", "script"}
+	var keys []string
+	for _, key := range order {
+		if _, ok := details[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	var rest []string
+	for key := range details {
+		if !slices.Contains(order, key) && key != "restarts" {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// formatSession describes a login session, e.g.
+// "Started by alice from 10.0.0.5 via sudo inside tmux session deploy"
+func formatSession(s model.Session) string {
+	var b strings.Builder
+	b.WriteString("Started")
+	if s.User != "" {
+		b.WriteString(" by " + s.User)
+	}
+	if s.RemoteAddr != "" {
+		b.WriteString(" from " + s.RemoteAddr)
+	}
+	if len(s.Via) > 0 {
+		b.WriteString(" via " + strings.Join(s.Via, " then "))
+	}
+	if s.Multiplexer != "" {
+		b.WriteString(" inside " + s.Multiplexer)
+		if s.MultiplexerSession != "" {
+			b.WriteString(" session " + s.MultiplexerSession)
+		}
+	}
+	return b.String()
+}
+
+// renderProbableParent shows the inferred launcher of a reparented process
+// below the ancestry chain, with the evidence for it
+func renderProbableParent(out Printer, pp model.ProbableParent, colorEnabled bool) {
+	name := "exited process"
+	if pp.Alive {
+		name = SanitizeTerminal(pp.Command)
+	}
+	if colorEnabled {
+		out.Printf("%sProbable original parent%s : %s (%spid %d%s)\n", colorMagenta, colorReset, name, colorBold, pp.PID, colorReset)
+	} else {
+		out.Printf("Probable original parent : %s (pid %d)\n", name, pp.PID)
+	}
+	for _, e := range pp.Evidence {
+		out.Printf("  %s\n", SanitizeTerminal(e))
+	}
+	out.Println("")
+}
+
+// formatLineage summarises how a process was detached, e.g.
+// "launched from a shell then detached (nohup)"
+func formatLineage(l model.Lineage) string {
+	var text string
+	switch {
+	case l.FromShell && l.Reparented:
+		text = "launched from a shell then detached"
+	case l.FromShell:
+		text = "launched from a shell"
+	case l.Reparented:
+		text = "detached from its original parent"
+	default:
+		text = "detached"
+	}
+	if len(l.Methods) > 0 {
+		text += " (" + strings.Join(l.Methods, ", ") + ")"
+	}
+	return text
+}
+
+// formatAgo renders how long ago something happened, e.g. "3 hours ago"
+func formatAgo(dur time.Duration) string {
+	switch {
+	case dur.Hours() >= 48:
+		days := int(dur.Hours()) / 24
+		return fmt.Sprintf("%d days ago", days)
+	case dur.Hours() >= 24:
+		return "1 day ago"
+	case dur.Hours() >= 2:
+		hours := int(dur.Hours())
+		return fmt.Sprintf("%d hours ago", hours)
+	case dur.Minutes() >= 60:
+		return "1 hour ago"
+	default:
+		mins := int(dur.Minutes())
+		if mins > 0 {
+			return fmt.Sprintf("%d min ago", mins)
+		}
+		return "just now"
+	}
+}
+
+// processLabel names a process with the app it runs, e.g. "python3 [celery worker]"
+func processLabel(p model.Process) string {
+	if p.App != "" {
+		return p.Command + " [" + p.App + "]"
+	}
+	return p.Command
+}
+
+// formatSize renders a byte count in KB below 1 MB and in MB above
+func formatSize(n int64) string {
+	if n < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+}
+
+// formatCapabilities summarizes a capability set, calling out the risky ones
+func formatCapabilities(caps []string) string {
+	if len(caps) == 0 {
+		return "none"
+	}
+	if len(caps) <= 5 {
+		return strings.Join(caps, ", ")
+	}
+	var notable []string
+	for _, c := range caps {
+		switch c {
+		case "CAP_SYS_ADMIN", "CAP_SYS_MODULE", "CAP_SYS_PTRACE", "CAP_NET_ADMIN":
+			notable = append(notable, c)
+		}
+	}
+	if len(notable) == 0 {
+		return fmt.Sprintf("%d capabilities", len(caps))
+	}
+	return fmt.Sprintf("%d capabilities (incl. %s)", len(caps), strings.Join(notable, ", "))
+}
+
+// ScanIncompleteNote tells that results drawn from scanning all processes
+// may be partial
+func ScanIncompleteNote(n int) string {
+	if n == 1 {
+		return "scan incomplete: 1 process unreadable/timed out"
+	}
+	return fmt.Sprintf("scan incomplete: %d processes unreadable/timed out", n)
+}
+
+// RenderWarnings prints only the warnings, with color if enabled.
+// Suppressed warnings are listed after them when provided.
+func RenderWarnings(w io.Writer, warnings []model.Warning, suppressed []model.Warning, colorEnabled bool) {
 	out := NewPrinter(w)
 	if len(warnings) == 0 {
 		if colorEnabled {
@@ -44,17 +217,34 @@ func RenderWarnings(w io.Writer, warnings []string, colorEnabled bool) {
 		} else {
 			out.Println("No warnings.")
 		}
-		return
-	}
-	if colorEnabled {
+	} else if colorEnabled {
 		out.Printf("%sWarnings%s:\n", colorRed, colorReset)
 		for _, w := range warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
+			out.Printf("  • %s\n", SanitizeTerminal(w.Message))
 		}
 	} else {
 		out.Println("Warnings:")
 		for _, w := range warnings {
-			out.Printf("  • %s\n", SanitizeTerminal(w))
+			out.Printf("  • %s\n", SanitizeTerminal(w.Message))
+		}
+	}
+	renderSuppressed(out, suppressed, colorEnabled)
+}
+
+// renderSuppressed prints warnings hidden by the allowlist along with the reason
+func renderSuppressed(out Printer, suppressed []model.Warning, colorEnabled bool) {
+	if len(suppressed) == 0 {
+		return
+	}
+	if colorEnabled {
+		out.Printf("%sSuppressed%s:\n", colorDimYellow, colorReset)
+		for _, w := range suppressed {
+			out.Printf("  %s• %s (%s)%s\n", colorBold, SanitizeTerminal(w.Message), SanitizeTerminal(w.SuppressReason), colorReset)
+		}
+	} else {
+		out.Println("Suppressed:")
+		for _, w := range suppressed {
+			out.Printf("  • %s (%s)\n", SanitizeTerminal(w.Message), SanitizeTerminal(w.SuppressReason))
 		}
 	}
 }
@@ -97,16 +287,23 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			out.Printf(" [%s]", health)
 		}
 	}
-	// Forked status: only display if forked
-	if proc.Forked == "forked" {
-		forkColor := colorDimYellow
+	// Detached from its launcher (nohup, setsid, daemonized)
+	if proc.Lineage.Detached() {
 		if colorEnabled {
-			out.Printf(" %s{forked}%s", forkColor, colorReset)
+			out.Printf(" %s{detached}%s", colorDimYellow, colorReset)
 		} else {
-			out.Printf(" {forked}")
+			out.Printf(" {detached}")
 		}
 	}
 	out.Println("")
+	if proc.App != "" {
+		app := SanitizeTerminal(proc.App)
+		if colorEnabled {
+			out.Printf("%sApp%s         : %s\n", colorGreen, colorReset, app)
+		} else {
+			out.Printf("App         : %s\n", app)
+		}
+	}
 	if proc.User != "" && proc.User != "unknown" {
 		if colorEnabled {
 			out.Printf("%sUser%s        : %s\n", colorCyan, colorReset, proc.User)
@@ -145,30 +342,34 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 			out.Printf("Command     : %s\n", proc.Command)
 		}
 	}
-	// Format as: 2 days ago (Mon 2025-02-02 11:42:10 +0530)
-	startedAt := proc.StartedAt
-	now := time.Now()
-	dur := now.Sub(startedAt)
-	var rel string
-	switch {
-	case dur.Hours() >= 48:
-		days := int(dur.Hours()) / 24
-		rel = fmt.Sprintf("%d days ago", days)
-	case dur.Hours() >= 24:
-		rel = "1 day ago"
-	case dur.Hours() >= 2:
-		hours := int(dur.Hours())
-		rel = fmt.Sprintf("%d hours ago", hours)
-	case dur.Minutes() >= 60:
-		rel = "1 hour ago"
-	default:
-		mins := int(dur.Minutes())
-		if mins > 0 {
-			rel = fmt.Sprintf("%d min ago", mins)
+	// Binary and owning package
+	if bin := proc.Binary; bin != nil {
+		binary := SanitizeTerminal(bin.Path)
+		switch {
+		case proc.ExeDeleted:
+			binary += " (replaced on disk)"
+		case bin.Package != "":
+			binary += fmt.Sprintf(" (%s %s, %s)", SanitizeTerminal(bin.Package), SanitizeTerminal(bin.PackageVersion), bin.Verification)
+		case bin.PackageDBAvailable:
+			binary += " (not owned by any package)"
+		}
+		if colorEnabled {
+			if bin.Verification == model.BinaryModified {
+				out.Printf("%sBinary%s      : %s%s%s\n", colorGreen, colorReset, colorRed, binary, colorReset)
+			} else {
+				out.Printf("%sBinary%s      : %s\n", colorGreen, colorReset, binary)
+			}
 		} else {
-			rel = "just now"
+			out.Printf("Binary      : %s\n", binary)
+		}
+		if verbose && bin.SHA256 != "" {
+			out.Printf("              %s, modified %s, sha256 %s\n", formatSize(bin.Size), bin.ModTime.Format("2006-01-02 15:04"), bin.SHA256)
 		}
 	}
+
+	// Format as: 2 days ago (Mon 2025-02-02 11:42:10 +0530)
+	startedAt := proc.StartedAt
+	rel := formatAgo(time.Since(startedAt))
 	dtStr := startedAt.Format("Mon 2006-01-02 15:04:05 -07:00")
 	if colorEnabled {
 		out.Printf("%sStarted%s     : %s (%s)\n", colorMagenta, colorReset, rel, dtStr)
@@ -176,12 +377,28 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		out.Printf("Started     : %s (%s)\n", rel, dtStr)
 	}
 
-	// Restart count
-	if r.RestartCount > 0 {
+	// Restart count, or for supervisors that keep none whether the program
+	// started well after its supervisor
+	restarts := ""
+	switch {
+	case r.RestartCount > 0:
+		restarts = fmt.Sprintf("%d (%s)", r.RestartCount, r.RestartSource)
+	case r.SupervisorStarted != nil && startedAt.Sub(*r.SupervisorStarted) > time.Minute:
+		restarts = fmt.Sprintf("not counted by %s, which started %s; restarted (or started by hand) since",
+			r.Source.Name, formatAgo(time.Since(*r.SupervisorStarted)))
+	}
+	if restarts != "" {
 		if colorEnabled {
-			out.Printf("%sRestarts%s    : %d\n", colorDimYellow, colorReset, r.RestartCount)
+			out.Printf("%sRestarts%s    : %s\n", colorDimYellow, colorReset, restarts)
 		} else {
-			out.Printf("Restarts    : %d\n", r.RestartCount)
+			out.Printf("Restarts    : %s\n", restarts)
+		}
+	}
+	if r.NestedSameName > 0 {
+		if colorEnabled {
+			out.Printf("%sNested%s      : %d (same-name ancestors, not restarts)\n", colorDimYellow, colorReset, r.NestedSameName)
+		} else {
+			out.Printf("Nested      : %d (same-name ancestors, not restarts)\n", r.NestedSameName)
 		}
 	}
 
@@ -220,6 +437,9 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 		out.Print("\n\n")
 	}
+	if proc.Lineage != nil && proc.Lineage.ProbableParent != nil {
+		renderProbableParent(out, *proc.Lineage.ProbableParent, colorEnabled)
+	}
 
 	// Source
 	sourceLabel := string(r.Source.Type)
@@ -238,11 +458,9 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
-	// Source details (launchd triggers, plist path, etc.)
+	// Source details (launchd triggers, plist path, app server master, etc.)
 	if len(r.Source.Details) > 0 {
-		// Display in consistent order
-		detailKeys := []string{"type", "plist", "triggers", "keepalive"}
-		for _, key := range detailKeys {
+		for _, key := range sourceDetailKeys(r.Source.Details) {
 			if val, ok := r.Source.Details[key]; ok {
 				label := formatDetailLabel(key)
 				if colorEnabled {
@@ -254,6 +472,25 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	if r.Session != nil {
+		if colorEnabled {
+			out.Printf("%sSession%s     : %s\n", colorCyan, colorReset, formatSession(*r.Session))
+		} else {
+			out.Printf("Session     : %s\n", formatSession(*r.Session))
+		}
+	}
+
+	if proc.Lineage.Detached() {
+		if colorEnabled {
+			out.Printf("%sLineage%s     : %s\n", colorCyan, colorReset, formatLineage(*proc.Lineage))
+		} else {
+			out.Printf("Lineage     : %s\n", formatLineage(*proc.Lineage))
+		}
+		for _, e := range proc.Lineage.Evidence {
+			out.Printf("              %s\n", SanitizeTerminal(e))
+		}
+	}
+
 	// Context group
 	if colorEnabled {
 		if proc.WorkingDir != "" && proc.WorkingDir != "unknown" {
@@ -279,6 +516,21 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		}
 	}
 
+	// Isolation (namespaces that differ from PID 1)
+	var isolated []string
+	for _, ns := range proc.Namespaces {
+		if ns.DiffersFromInit {
+			isolated = append(isolated, ns.Type)
+		}
+	}
+	if len(isolated) > 0 {
+		if colorEnabled {
+			out.Printf("%sIsolation%s   : %s namespaces differ from host\n", colorCyan, colorReset, strings.Join(isolated, ", "))
+		} else {
+			out.Printf("Isolation   : %s namespaces differ from host\n", strings.Join(isolated, ", "))
+		}
+	}
+
 	// Listening section (address:port)
 	if len(proc.ListeningPorts) > 0 && len(proc.BindAddresses) == len(proc.ListeningPorts) {
 		for i := range proc.ListeningPorts {
@@ -310,15 +562,26 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 		if colorEnabled {
 			out.Printf("\n%sWarnings%s    :\n", colorRed, colorReset)
 			for _, w := range r.Warnings {
-				out.Printf("  • %s\n", SanitizeTerminal(w))
+				out.Printf("  • %s\n", SanitizeTerminal(w.Message))
 			}
 		} else {
 			out.Println("\nWarnings    :")
 			for _, w := range r.Warnings {
-				out.Printf("  • %s\n", SanitizeTerminal(w))
+				out.Printf("  • %s\n", SanitizeTerminal(w.Message))
 			}
 		}
 	}
+	if len(r.Suppressed) > 0 {
+		out.Println("")
+		renderSuppressed(out, r.Suppressed, colorEnabled)
+	}
+	if r.ScanIncomplete > 0 {
+		if colorEnabled {
+			out.Printf("\n%sNote%s        : %s\n", colorDimYellow, colorReset, ScanIncompleteNote(r.ScanIncomplete))
+		} else {
+			out.Printf("\nNote        : %s\n", ScanIncompleteNote(r.ScanIncomplete))
+		}
+	}
 
 	// Extended information for verbose mode
 	if verbose {
@@ -358,6 +621,97 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 					out.Printf("Thermal     : %s\n", thermalState)
 				}
 			}
+
+			if r.ResourceContext.OOMScore > 0 || r.ResourceContext.OOMScoreAdj != 0 {
+				if colorEnabled {
+					out.Printf("%sOOM Score%s   : %d (adj %d)\n", colorGreen, colorReset, r.ResourceContext.OOMScore, r.ResourceContext.OOMScoreAdj)
+				} else {
+					out.Printf("OOM Score   : %d (adj %d)\n", r.ResourceContext.OOMScore, r.ResourceContext.OOMScoreAdj)
+				}
+			}
+
+			// Cgroup limits, throttling and pressure
+			if cg := r.ResourceContext.Cgroup; cg != nil {
+				if colorEnabled {
+					out.Printf("\n%sCgroup%s:\n", colorGreen, colorReset)
+				} else {
+					out.Printf("\nCgroup:\n")
+				}
+				out.Printf("  Path     : %s\n", cg.Path)
+				if cg.MemoryMax > 0 {
+					out.Printf("  Memory   : %.1f MB of %.1f MB (%.0f%%)\n", float64(cg.MemoryCurrent)/(1024*1024), float64(cg.MemoryMax)/(1024*1024), float64(cg.MemoryCurrent)/float64(cg.MemoryMax)*100)
+				} else if cg.MemoryCurrent > 0 {
+					out.Printf("  Memory   : %.1f MB (no limit)\n", float64(cg.MemoryCurrent)/(1024*1024))
+				}
+				if cg.CPUQuota > 0 {
+					out.Printf("  CPU      : %.2f CPUs, throttled %d of %d periods\n", cg.CPUQuota, cg.NrThrottled, cg.NrPeriods)
+				} else {
+					out.Printf("  CPU      : no quota\n")
+				}
+				if cg.CPUPressure > 0 || cg.MemoryPressure > 0 || cg.IOPressure > 0 {
+					out.Printf("  Pressure : cpu %.2f%%, memory %.2f%%, io %.2f%% (avg10)\n", cg.CPUPressure, cg.MemoryPressure, cg.IOPressure)
+				}
+			}
+		}
+
+		// Security posture (capabilities, seccomp, LSM)
+		if sec := proc.Security; sec != nil {
+			noNewPrivs := "no"
+			if sec.NoNewPrivs {
+				noNewPrivs = "yes"
+			}
+			lsm := sec.LSMLabel
+			if lsm == "" {
+				lsm = "none"
+			}
+			if colorEnabled {
+				out.Printf("\n%sSecurity%s:\n", colorGreen, colorReset)
+			} else {
+				out.Printf("\nSecurity:\n")
+			}
+			out.Printf("  Capabilities : %s\n", formatCapabilities(sec.CapEff))
+			out.Printf("  Bounding set : %s\n", formatCapabilities(sec.CapBnd))
+			out.Printf("  Seccomp      : %s\n", sec.Seccomp)
+			out.Printf("  NoNewPrivs   : %s\n", noNewPrivs)
+			out.Printf("  LSM          : %s\n", lsm)
+		}
+
+		// Namespaces
+		if len(proc.Namespaces) > 0 {
+			if colorEnabled {
+				out.Printf("\n%sNamespaces%s:\n", colorGreen, colorReset)
+			} else {
+				out.Printf("\nNamespaces:\n")
+			}
+			for _, ns := range proc.Namespaces {
+				var diffs []string
+				if ns.DiffersFromInit {
+					diffs = append(diffs, "differs from init")
+				}
+				if ns.DiffersFromParent {
+					diffs = append(diffs, "differs from parent")
+				}
+				if len(diffs) > 0 {
+					out.Printf("  %-6s : %d (%s)\n", ns.Type, ns.Inode, strings.Join(diffs, ", "))
+				} else {
+					out.Printf("  %-6s : %d\n", ns.Type, ns.Inode)
+				}
+			}
+		}
+
+		// Deleted executable and libraries still in use
+		if proc.ExeDeleted || len(proc.DeletedLibs) > 0 {
+			if colorEnabled {
+				out.Printf("\n%sDeleted In Use%s:\n", colorRed, colorReset)
+			} else {
+				out.Printf("\nDeleted In Use:\n")
+			}
+			if proc.ExeDeleted {
+				out.Printf("  exe : %s\n", withOwner(proc.Exe, proc.DeletedOwners))
+			}
+			for _, lib := range proc.DeletedLibs {
+				out.Printf("  lib : %s\n", withOwner(lib, proc.DeletedOwners))
+			}
 		}
 
 		// Memory information
@@ -434,6 +788,17 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 					}
 				}
 			}
+			if len(r.FileContext.WatchedDirs) > 0 {
+				firstWatched := SanitizeTerminal(r.FileContext.WatchedDirs[0])
+				if colorEnabled {
+					out.Printf("%sWatching%s    : %s\n", colorCyan, colorReset, firstWatched)
+				} else {
+					out.Printf("Watching    : %s\n", firstWatched)
+				}
+				for _, d := range r.FileContext.WatchedDirs[1:] {
+					out.Printf("              %s\n", SanitizeTerminal(d))
+				}
+			}
 		}
 
 		// File descriptors
@@ -444,6 +809,9 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 				} else {
 					out.Printf("\n%sFile Descriptors%s: %d/%d\n", colorGreen, colorReset, proc.FDCount, proc.FDLimit)
 				}
+				if summary := formatFDCounts(proc.FDCounts); summary != "" {
+					out.Printf("  By type : %s (see --fds)\n", summary)
+				}
 				if len(proc.FileDescs) > 0 && len(proc.FileDescs) <= 10 {
 					for _, fd := range proc.FileDescs {
 						out.Printf("  %s\n", SanitizeTerminal(fd))
@@ -461,6 +829,9 @@ func RenderStandard(w io.Writer, r model.Result, colorEnabled bool, verbose bool
 				} else {
 					out.Printf("\nFile Descriptors: %d/%d\n", proc.FDCount, proc.FDLimit)
 				}
+				if summary := formatFDCounts(proc.FDCounts); summary != "" {
+					out.Printf("  By type : %s (see --fds)\n", summary)
+				}
 				if len(proc.FileDescs) > 0 && len(proc.FileDescs) <= 10 {
 					for _, fd := range proc.FileDescs {
 						out.Printf("  %s\n", SanitizeTerminal(fd))
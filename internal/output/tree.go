@@ -32,9 +32,9 @@ func PrintTree(w io.Writer, chain []model.Process, children []model.Process, col
 			if i == len(chain)-1 {
 				cmdColor = colorGreenTree
 			}
-			p.Printf("%s%s%s (%spid %d%s)\n", cmdColor, proc.Command, colorResetTree, colorBoldTree, proc.PID, colorResetTree)
+			p.Printf("%s%s%s (%spid %d%s)\n", cmdColor, processLabel(proc), colorResetTree, colorBoldTree, proc.PID, colorResetTree)
 		} else {
-			p.Printf("%s (pid %d)\n", proc.Command, proc.PID)
+			p.Printf("%s (pid %d)\n", processLabel(proc), proc.PID)
 		}
 	}
 
@@ -64,9 +64,9 @@ func PrintTree(w io.Writer, chain []model.Process, children []model.Process, col
 		}
 
 		if colorEnabled {
-			p.Printf("%s%s%s%s%s (%spid %d%s)\n", baseIndent, colorMagentaTree, connector, colorResetTree, child.Command, colorBoldTree, child.PID, colorResetTree)
+			p.Printf("%s%s%s%s%s (%spid %d%s)\n", baseIndent, colorMagentaTree, connector, colorResetTree, processLabel(child), colorBoldTree, child.PID, colorResetTree)
 		} else {
-			p.Printf("%s%s%s (pid %d)\n", baseIndent, connector, child.Command, child.PID)
+			p.Printf("%s%s%s (pid %d)\n", baseIndent, connector, processLabel(child), child.PID)
 		}
 	}
 }
@@ -23,6 +23,7 @@ func ResolveAncestry(pid int) ([]model.Process, error) {
 			break
 		}
 
+		p.App = DetectApp(CmdlineArgs(p.PID, p.Cmdline))
 		chain = append([]model.Process{p}, chain...)
 
 		if p.PPID == 0 || p.PID == 1 {
@@ -35,5 +36,15 @@ func ResolveAncestry(pid int) ([]model.Process, error) {
 		return nil, fmt.Errorf("no process ancestry found")
 	}
 
+	annotateNamespaces(chain)
+
 	return chain, nil
 }
+
+// legacyForked computes the deprecated Process.Forked value
+func legacyForked(ppid int, isInit bool) string {
+	if ppid != 1 && !isInit {
+		return "forked"
+	}
+	return "not-forked"
+}
@@ -0,0 +1,165 @@
+package proc
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// interpreter describes how to find the application an interpreter runs:
+// options that consume the following argument, options after which the
+// next argument is the app (python -m, java -jar), inline-code options
+// for which there is no app to name (python -c, node -e) and the option
+// starting a built-in web server (php -S) with the one naming its docroot
+type interpreter struct {
+	takesValue map[string]bool
+	appFlag    map[string]bool
+	inline     map[string]bool
+	server     string
+	docroot    string
+}
+
+func flagSet(flags ...string) map[string]bool {
+	set := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		set[f] = true
+	}
+	return set
+}
+
+var interpreters = map[string]interpreter{
+	"python": {
+		takesValue: flagSet("-W", "-X", "--check-hash-based-pycs"),
+		appFlag:    flagSet("-m"),
+		inline:     flagSet("-c"),
+	},
+	"node": {
+		takesValue: flagSet("-r", "--require", "--loader", "--experimental-loader", "--import", "--env-file", "--title", "-C", "--conditions"),
+		inline:     flagSet("-e", "--eval", "-p", "--print"),
+	},
+	"java": {
+		takesValue: flagSet("-cp", "-classpath", "--class-path", "-p", "--module-path", "--add-modules", "--add-opens", "--add-exports", "--add-reads", "--upgrade-module-path", "--limit-modules"),
+		appFlag:    flagSet("-jar", "-m", "--module"),
+	},
+	"ruby": {
+		takesValue: flagSet("-I", "-r", "-C", "-E", "--encoding"),
+		appFlag:    flagSet("-S"),
+		inline:     flagSet("-e"),
+	},
+	"php": {
+		takesValue: flagSet("-c", "-d", "-t", "-z", "-S"),
+		appFlag:    flagSet("-f"),
+		inline:     flagSet("-r", "-R", "-B", "-E"),
+		server:     "-S",
+		docroot:    "-t",
+	},
+}
+
+// Versioned interpreter binaries: python3.11, nodejs, php8.2, ruby3.2, java
+var interpreterPattern = regexp.MustCompile(`^(python|pypy|node|nodejs|java|ruby|php|php-cgi)[0-9.]*(\.exe)?$`)
+
+// subcommandPattern matches a bare word argument such as "worker" or "runserver"
+var subcommandPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_:-]*$`)
+
+// interpreterFor returns the interpreter family of an executable name
+func interpreterFor(name string) (interpreter, bool) {
+	m := interpreterPattern.FindStringSubmatch(strings.ToLower(filepath.Base(name)))
+	if m == nil {
+		return interpreter{}, false
+	}
+	family := m[1]
+	switch family {
+	case "pypy":
+		family = "python"
+	case "nodejs":
+		family = "node"
+	case "php-cgi":
+		family = "php"
+	}
+	in, ok := interpreters[family]
+	return in, ok
+}
+
+// IsInterpreter reports whether a command is a python, node, java, ruby or php interpreter
+func IsInterpreter(command string) bool {
+	_, ok := interpreterFor(command)
+	return ok
+}
+
+// DetectApp names the application an interpreter process runs from its
+// arguments, e.g. "python -m celery worker" → "celery worker", "java -jar
+// app.jar" → "app.jar", "node /srv/api/index.js" → "/srv/api/index.js",
+// "php -S :8080 -t public" → "public (built-in server)". It returns "" for
+// non-interpreters and inline code.
+func DetectApp(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	in, ok := interpreterFor(args[0])
+	if !ok {
+		return ""
+	}
+
+	app, server, docroot := "", false, ""
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		arg := rest[i]
+		if name, _, found := strings.Cut(arg, "="); found && (in.appFlag[name] || in.takesValue[name]) {
+			// --module=foo, --require=bar
+			if in.appFlag[name] {
+				app, rest = arg[len(name)+1:], rest[i+1:]
+				break
+			}
+			continue
+		}
+		switch {
+		case in.inline[arg]:
+			return ""
+		case in.appFlag[arg]:
+			if i+1 < len(rest) {
+				app, rest = rest[i+1], rest[i+2:]
+			}
+		case in.takesValue[arg]:
+			server = server || arg == in.server
+			if arg == in.docroot && i+1 < len(rest) {
+				docroot = rest[i+1]
+			}
+			i++
+			continue
+		case arg == "--":
+			if i+1 < len(rest) {
+				app, rest = rest[i+1], rest[i+2:]
+			}
+		case strings.HasPrefix(arg, "-"):
+			continue
+		default:
+			app, rest = arg, rest[i+1:]
+		}
+		break
+	}
+	if server {
+		// The docroot names the app; a router script is the app itself
+		if app == "" {
+			app = docroot
+		}
+		if app == "" {
+			return "built-in server"
+		}
+		return app + " (built-in server)"
+	}
+	if app == "" {
+		return ""
+	}
+
+	// Console entry points (/usr/local/bin/celery, bin/rails) read better by name
+	if dir := filepath.Base(filepath.Dir(app)); (dir == "bin" || dir == "sbin") && filepath.Ext(app) == "" {
+		app = filepath.Base(app)
+	}
+
+	// Append a directly following subcommand, e.g. "celery worker", "manage.py runserver".
+	// Arguments after options are not considered, option values look the same.
+	if len(rest) > 0 && subcommandPattern.MatchString(rest[0]) {
+		app += " " + rest[0]
+	}
+	return app
+}
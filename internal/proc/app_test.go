@@ -0,0 +1,44 @@
+package proc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectApp(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    string
+	}{
+		{cmdline: "python3 -m celery worker --loglevel=info", want: "celery worker"},
+		{cmdline: "/usr/bin/python3.11 -u -W ignore -m http.server 8000", want: "http.server"},
+		{cmdline: "python3 /usr/local/bin/gunicorn -w 4 app:app", want: "gunicorn"},
+		{cmdline: "python manage.py runserver 0.0.0.0:8000", want: "manage.py runserver"},
+		{cmdline: "python3 -c import time; time.sleep(100)", want: ""},
+		{cmdline: "python3", want: ""},
+		{cmdline: "java -Xmx2g -Dspring.profiles.active=prod -jar /opt/app/app.jar", want: "/opt/app/app.jar"},
+		{cmdline: "java -cp /opt/kafka/libs/* kafka.Kafka config/server.properties", want: "kafka.Kafka"},
+		{cmdline: "/usr/lib/jvm/bin/java --module-path mods --module=com.example/com.example.Main", want: "com.example/com.example.Main"},
+		{cmdline: "node /srv/api/index.js", want: "/srv/api/index.js"},
+		{cmdline: "node --require dotenv/config --inspect=9229 dist/server.js", want: "dist/server.js"},
+		{cmdline: "node -e console.log(1)", want: ""},
+		{cmdline: "ruby -I lib bin/rails server", want: "rails server"},
+		{cmdline: "php -d memory_limit=-1 artisan queue:work", want: "artisan queue:work"},
+		{cmdline: "php -S 0.0.0.0:8080 -t public", want: "public (built-in server)"},
+		{cmdline: "php -S localhost:8000 router.php", want: "router.php (built-in server)"},
+		{cmdline: "php8.2 -S :8000", want: "built-in server"},
+		{cmdline: "nginx -g daemon off;", want: ""},
+		{cmdline: "php-fpm8.2 -F", want: ""},
+	}
+	for _, tt := range tests {
+		if got := DetectApp(strings.Fields(tt.cmdline)); got != tt.want {
+			t.Errorf("DetectApp(%q) = %q, want %q", tt.cmdline, got, tt.want)
+		}
+	}
+
+	// Script paths keep their spaces when split where the process got them
+	argv := []string{"/usr/bin/python3", "/srv/My Scripts/sync job.py", "--once"}
+	if got, want := DetectApp(argv), "/srv/My Scripts/sync job.py"; got != want {
+		t.Errorf("DetectApp(%q) = %q, want %q", argv, got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package proc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ReadBinaryInfo stats the executable of a process and, where a package
+// database is available, checks which package owns it and whether the file
+// still matches the packaged checksum. The whole file is only hashed for
+// its SHA-256 with hash set, or as evidence when it no longer matches.
+func ReadBinaryInfo(pid int, exe string, hash bool) *model.BinaryInfo {
+	if exe == "" {
+		return nil
+	}
+
+	// Read through the process where possible so a replaced file on disk
+	// does not get attributed to a process still running the old one
+	f, err := os.Open(executableReadPath(pid, exe))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	bin := &model.BinaryInfo{
+		Path:    exe,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	// Only check the package database if the file on disk is still the one
+	// the process runs, a replaced binary would otherwise look modified
+	if onDisk, err := os.Stat(exe); err == nil && os.SameFile(info, onDisk) {
+		lookupPackage(bin, f.Name())
+	}
+
+	if hash || bin.Verification == model.BinaryModified {
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err == nil {
+			bin.SHA256 = hex.EncodeToString(h.Sum(nil))
+		}
+	}
+	return bin
+}
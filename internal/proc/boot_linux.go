@@ -4,13 +4,31 @@ package proc
 
 import (
 	"bufio"
+	"encoding/binary"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-func bootTime() time.Time {
+// atClkTck is the auxiliary vector entry holding sysconf(_SC_CLK_TCK)
+const atClkTck = 17
+
+// bootTime is when the system booted. /proc/uptime has 10ms resolution where
+// btime in /proc/stat is whole seconds, which put every start time up to a
+// second early. It is computed once so all start times share the same base.
+var bootTime = sync.OnceValue(func() time.Time {
+	if data, err := os.ReadFile("/proc/uptime"); err == nil {
+		if up, ok := parseUptime(string(data)); ok {
+			return time.Now().Add(-up)
+		}
+	}
+	return statBootTime()
+})
+
+// statBootTime reads btime from /proc/stat
+func statBootTime() time.Time {
 	f, err := os.Open("/proc/stat")
 	if err != nil {
 		return time.Now()
@@ -29,6 +47,52 @@ func bootTime() time.Time {
 	return time.Now()
 }
 
+// parseUptime reads the seconds since boot from /proc/uptime ("12345.67 45678.90")
+func parseUptime(content string) (time.Duration, bool) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	sec, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || sec < 0 {
+		return 0, false
+	}
+	return time.Duration(sec * float64(time.Second)), true
+}
+
+// clockTicks is the kernel's USER_HZ, the unit of the times in
+// /proc/<pid>/stat. The kernel hands it to every process in its auxiliary
+// vector, which is what getauxval(AT_CLKTCK) and sysconf read.
+var clockTicks = sync.OnceValue(func() time.Duration {
+	if data, err := os.ReadFile("/proc/self/auxv"); err == nil {
+		if hz, ok := auxvValue(data, atClkTck, binary.NativeEndian, strconv.IntSize/8); ok && hz > 0 {
+			return time.Duration(hz)
+		}
+	}
+	return 100
+})
+
 func ticksPerSecond() time.Duration {
-	return 100 // Linux default; portable enough for now
+	return clockTicks()
+}
+
+// auxvValue looks up key in an auxiliary vector: pairs of native words,
+// ended by AT_NULL
+func auxvValue(data []byte, key uint64, order binary.ByteOrder, wordSize int) (uint64, bool) {
+	word := func(b []byte) uint64 {
+		if wordSize == 4 {
+			return uint64(order.Uint32(b))
+		}
+		return order.Uint64(b)
+	}
+	for i := 0; i+2*wordSize <= len(data); i += 2 * wordSize {
+		k := word(data[i:])
+		if k == 0 {
+			break
+		}
+		if k == key {
+			return word(data[i+wordSize:]), true
+		}
+	}
+	return 0, false
 }
@@ -0,0 +1,41 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestAuxvValue(t *testing.T) {
+	auxv64 := make([]byte, 0, 64)
+	auxv32 := make([]byte, 0, 32)
+	for _, v := range []uint64{6, 4096, 17, 250, 0, 0, 17, 1000} {
+		auxv64 = binary.LittleEndian.AppendUint64(auxv64, v)
+		auxv32 = binary.BigEndian.AppendUint32(auxv32, uint32(v))
+	}
+
+	if got, ok := auxvValue(auxv64, atClkTck, binary.LittleEndian, 8); !ok || got != 250 {
+		t.Errorf("auxvValue(64-bit) = %d, %v, want 250", got, ok)
+	}
+	if got, ok := auxvValue(auxv32, atClkTck, binary.BigEndian, 4); !ok || got != 250 {
+		t.Errorf("auxvValue(32-bit) = %d, %v, want 250", got, ok)
+	}
+	// Entries after AT_NULL are not part of the vector
+	if _, ok := auxvValue(auxv64, 33, binary.LittleEndian, 8); ok {
+		t.Error("auxvValue() found a missing key")
+	}
+	if got := ticksPerSecond(); got <= 0 {
+		t.Errorf("ticksPerSecond() = %d", got)
+	}
+}
+
+func TestParseUptime(t *testing.T) {
+	if got, ok := parseUptime("12345.67 45678.90\n"); !ok || got != 12345670*time.Millisecond {
+		t.Errorf("parseUptime() = %v, %v, want 3h25m45.67s", got, ok)
+	}
+	if _, ok := parseUptime(""); ok {
+		t.Error("parseUptime(empty) succeeded")
+	}
+}
@@ -0,0 +1,233 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// v1 reports "unlimited" as a huge page-aligned number rather than "max"
+const cgroupV1Unlimited = uint64(1) << 62
+
+// cgroupDirs are the cgroup directories of a process. On a pure v2 host all
+// of them point at the same unified directory, on hybrid hosts memory and cpu
+// live in their v1 hierarchies while pressure files stay in the unified one.
+type cgroupDirs struct {
+	path    string
+	unified string
+	memory  string
+	cpu     string
+}
+
+func (d cgroupDirs) memoryV1() bool { return d.memory != "" && d.memory != d.unified }
+func (d cgroupDirs) cpuV1() bool    { return d.cpu != "" && d.cpu != d.unified }
+
+// readCgroupDirs parses /proc/<pid>/cgroup ("hierarchy-id:controllers:path" lines)
+func readCgroupDirs(pid int) (cgroupDirs, bool) {
+	data, err := Snapshot().Cgroup(pid)
+	if err != nil {
+		return cgroupDirs{}, false
+	}
+
+	var dirs cgroupDirs
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		controllers, path := parts[1], parts[2]
+		if parts[0] == "0" && controllers == "" {
+			root := cgroupRoot
+			if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+				root = filepath.Join(cgroupRoot, "unified")
+			}
+			dirs.unified = filepath.Join(root, path)
+			if dirs.path == "" {
+				dirs.path = path
+			}
+			continue
+		}
+		for _, c := range strings.Split(controllers, ",") {
+			switch c {
+			case "memory":
+				dirs.memory = filepath.Join(cgroupRoot, controllers, path)
+				dirs.path = path
+			case "cpu":
+				dirs.cpu = filepath.Join(cgroupRoot, controllers, path)
+			}
+		}
+	}
+
+	if dirs.memory == "" {
+		dirs.memory = dirs.unified
+	}
+	if dirs.cpu == "" {
+		dirs.cpu = dirs.unified
+	}
+	if dirs.unified == "" && dirs.memory == "" {
+		return cgroupDirs{}, false
+	}
+	return dirs, true
+}
+
+func readCgroupFile(dir, name string) string {
+	if dir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readCgroupUint(dir, name string) uint64 {
+	v, _ := strconv.ParseUint(readCgroupFile(dir, name), 10, 64)
+	return v
+}
+
+// readCgroupKeyed parses flat keyed files such as cpu.stat and memory.events
+func readCgroupKeyed(dir, name string) map[string]uint64 {
+	values := map[string]uint64{}
+	scanner := bufio.NewScanner(strings.NewReader(readCgroupFile(dir, name)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+	return values
+}
+
+// parsePressure returns the "some avg10" percentage from a PSI file
+func parsePressure(content string) float64 {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "some ") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if v, ok := strings.CutPrefix(field, "avg10="); ok {
+				f, _ := strconv.ParseFloat(v, 64)
+				return f
+			}
+		}
+	}
+	return 0
+}
+
+// parseCPUMax parses cgroup v2 cpu.max ("max 100000" or "200000 100000") into CPUs
+func parseCPUMax(content string) float64 {
+	fields := strings.Fields(content)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// readCgroupMemory returns the memory limit (0 when unlimited) and usage of a cgroup
+func readCgroupMemory(dirs cgroupDirs) (max, current uint64) {
+	if dirs.memoryV1() {
+		if limit := readCgroupUint(dirs.memory, "memory.limit_in_bytes"); limit < cgroupV1Unlimited {
+			max = limit
+		}
+		return max, readCgroupUint(dirs.memory, "memory.usage_in_bytes")
+	}
+	// "max" parses as 0
+	return readCgroupUint(dirs.memory, "memory.max"), readCgroupUint(dirs.memory, "memory.current")
+}
+
+// readCgroupCPUQuota returns the CPUs a cgroup may use, 0 when unlimited
+func readCgroupCPUQuota(dirs cgroupDirs) float64 {
+	if dirs.cpuV1() {
+		quota, _ := strconv.ParseFloat(readCgroupFile(dirs.cpu, "cpu.cfs_quota_us"), 64)
+		period, _ := strconv.ParseFloat(readCgroupFile(dirs.cpu, "cpu.cfs_period_us"), 64)
+		if quota > 0 && period > 0 {
+			return quota / period
+		}
+		return 0
+	}
+	return parseCPUMax(readCgroupFile(dirs.cpu, "cpu.max"))
+}
+
+// cgroupLimits returns the memory limit and CPU quota of a process's
+// cgroup, zero when unlimited
+func cgroupLimits(pid int) (memoryMax uint64, cpuQuota float64) {
+	dirs, ok := readCgroupDirs(pid)
+	if !ok {
+		return 0, 0
+	}
+	memoryMax, _ = readCgroupMemory(dirs)
+	return memoryMax, readCgroupCPUQuota(dirs)
+}
+
+// highCPU judges the CPU time a process has used against what it could have
+// used since it started: one CPU, or its cgroup's quota when it has one.
+// Processes younger than a few minutes are left alone, startup is busy.
+func highCPU(cpu, age time.Duration, quota float64) bool {
+	if age < 5*time.Minute {
+		return false
+	}
+	allowed := 1.0
+	if quota > 0 {
+		allowed = quota
+	}
+	return cpu.Seconds() > 0.8*allowed*age.Seconds()
+}
+
+// highMemory judges a resident set against the cgroup memory limit when
+// there is one (the OOM killer steps in at the limit, whatever the host
+// has), otherwise against a fixed 1GB
+func highMemory(rss, limit uint64) bool {
+	if limit > 0 {
+		return rss > limit/5*4
+	}
+	return rss > 1<<30
+}
+
+// readCgroupResources collects memory/cpu limits, throttling and pressure for a process's cgroup
+func readCgroupResources(pid int) *model.CgroupResources {
+	dirs, ok := readCgroupDirs(pid)
+	if !ok {
+		return nil
+	}
+
+	res := &model.CgroupResources{Path: dirs.path}
+
+	res.MemoryMax, res.MemoryCurrent = readCgroupMemory(dirs)
+
+	res.CPUQuota = readCgroupCPUQuota(dirs)
+	if dirs.cpuV1() {
+		stat := readCgroupKeyed(dirs.cpu, "cpu.stat")
+		res.NrPeriods = stat["nr_periods"]
+		res.NrThrottled = stat["nr_throttled"]
+		res.ThrottledUsec = stat["throttled_time"] / 1000
+	} else {
+		stat := readCgroupKeyed(dirs.cpu, "cpu.stat")
+		res.NrPeriods = stat["nr_periods"]
+		res.NrThrottled = stat["nr_throttled"]
+		res.ThrottledUsec = stat["throttled_usec"]
+	}
+
+	res.CPUPressure = parsePressure(readCgroupFile(dirs.unified, "cpu.pressure"))
+	res.MemoryPressure = parsePressure(readCgroupFile(dirs.unified, "memory.pressure"))
+	res.IOPressure = parsePressure(readCgroupFile(dirs.unified, "io.pressure"))
+
+	return res
+}
@@ -0,0 +1,79 @@
+//go:build linux
+
+package proc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePressure(t *testing.T) {
+	content := "some avg10=12.50 avg60=3.00 avg300=1.00 total=123456\n" +
+		"full avg10=4.00 avg60=1.00 avg300=0.50 total=6789\n"
+	if got := parsePressure(content); got != 12.5 {
+		t.Fatalf("parsePressure() = %v, want 12.5", got)
+	}
+	if got := parsePressure(""); got != 0 {
+		t.Fatalf("parsePressure(empty) = %v, want 0", got)
+	}
+}
+
+func TestParseCPUMax(t *testing.T) {
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{content: "max 100000", want: 0},
+		{content: "200000 100000", want: 2},
+		{content: "50000 100000", want: 0.5},
+		{content: "", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseCPUMax(tt.content); got != tt.want {
+			t.Errorf("parseCPUMax(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHighMemory(t *testing.T) {
+	const mb = 1 << 20
+	tests := []struct {
+		rss, limit uint64
+		want       bool
+	}{
+		{rss: 500 * mb, limit: 512 * mb, want: true},
+		{rss: 300 * mb, limit: 512 * mb, want: false},
+		{rss: 2048 * mb, limit: 64 * 1024 * mb, want: false},
+		{rss: 2048 * mb, limit: 0, want: true},
+		{rss: 500 * mb, limit: 0, want: false},
+	}
+	for _, tt := range tests {
+		if got := highMemory(tt.rss, tt.limit); got != tt.want {
+			t.Errorf("highMemory(%d, %d) = %v, want %v", tt.rss, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestHighCPU(t *testing.T) {
+	tests := []struct {
+		cpu, age time.Duration
+		quota    float64
+		want     bool
+	}{
+		// A busy loop pinning one CPU
+		{cpu: 55 * time.Minute, age: time.Hour, want: true},
+		// Hours of CPU time over months is an idle daemon, not a hot one
+		{cpu: 3 * time.Hour, age: 90 * 24 * time.Hour, want: false},
+		// Near its half-CPU quota, so being throttled
+		{cpu: 25 * time.Minute, age: time.Hour, quota: 0.5, want: true},
+		// One busy CPU out of a four CPU quota
+		{cpu: time.Hour, age: time.Hour, quota: 4, want: false},
+		// Too young to judge
+		{cpu: time.Minute, age: time.Minute, want: false},
+	}
+	for _, tt := range tests {
+		if got := highCPU(tt.cpu, tt.age, tt.quota); got != tt.want {
+			t.Errorf("highCPU(%v, %v, %v) = %v, want %v", tt.cpu, tt.age, tt.quota, got, tt.want)
+		}
+	}
+}
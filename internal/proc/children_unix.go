@@ -23,6 +23,9 @@ func ResolveChildren(pid int) ([]model.Process, error) {
 	children := make([]model.Process, 0)
 	for _, proc := range processes {
 		if proc.PPID == pid {
+			if IsInterpreter(proc.Command) {
+				proc.App = DetectApp(CmdlineArgs(proc.PID, GetCmdline(proc.PID)))
+			}
 			children = append(children, proc)
 		}
 	}
@@ -2,22 +2,23 @@
 
 package proc
 
-import (
-	"fmt"
-	"os"
-	"strings"
-)
+import "strings"
 
 // GetCmdline returns the command line for a given PID
 func GetCmdline(pid int) string {
-	cmdlineBytes, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
-	if err != nil {
-		return "(unknown)"
-	}
-	cmd := strings.ReplaceAll(string(cmdlineBytes), "\x00", " ")
-	cmdline := strings.TrimSpace(cmd)
+	cmdline := Snapshot().Cmdline(pid)
 	if cmdline == "" {
 		return "(unknown)"
 	}
 	return cmdline
 }
+
+// CmdlineArgs returns the arguments of a process's command line, split where
+// the process got them so paths with spaces stay whole. cmdline is split on
+// spaces when /proc no longer has them.
+func CmdlineArgs(pid int, cmdline string) []string {
+	if argv := Snapshot().Argv(pid); len(argv) > 0 {
+		return argv
+	}
+	return strings.Fields(cmdline)
+}
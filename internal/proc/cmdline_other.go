@@ -0,0 +1,11 @@
+//go:build !linux
+
+package proc
+
+import "strings"
+
+// CmdlineArgs returns the arguments of a process's command line. ps only
+// reports them joined by spaces, so cmdline is split on spaces.
+func CmdlineArgs(pid int, cmdline string) []string {
+	return strings.Fields(cmdline)
+}
@@ -0,0 +1,115 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// containerInspectTimeout bounds each docker/podman inspect, which hangs
+// for as long as an unresponsive daemon does
+var containerInspectTimeout = 2 * time.Second
+
+// containerInfo is what witr reads from docker or podman inspect
+type containerInfo struct {
+	runtime      string
+	id           string
+	image        string
+	restartCount int
+}
+
+var (
+	inspectMu    sync.Mutex
+	inspectCache = make(map[string]*containerInfo)
+)
+
+// inspectContainer asks docker, then podman, about a container by name or
+// ID. The result, or its absence, is shared by every lookup of the
+// invocation so each container is inspected once.
+func inspectContainer(nameOrID string) *containerInfo {
+	inspectMu.Lock()
+	defer inspectMu.Unlock()
+	if info, ok := inspectCache[nameOrID]; ok {
+		return info
+	}
+	var info *containerInfo
+	for _, runtime := range []string{"docker", "podman"} {
+		ctx, cancel := context.WithTimeout(context.Background(), containerInspectTimeout)
+		out, err := exec.CommandContext(ctx, runtime, "inspect", "--format", "{{.Id}}\t{{.Config.Image}}\t{{.RestartCount}}", nameOrID).Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+		fields := strings.Split(strings.TrimSpace(string(out)), "\t")
+		if len(fields) < 3 || fields[0] == "" {
+			continue
+		}
+		restarts, err := strconv.Atoi(fields[2])
+		if err != nil {
+			restarts = -1
+		}
+		info = &containerInfo{runtime: runtime, id: fields[0], image: fields[1], restartCount: restarts}
+		inspectCache[info.id] = info
+		break
+	}
+	inspectCache[nameOrID] = info
+	return info
+}
+
+// ContainerID extracts the full container ID from a process's cgroup paths
+// (docker-<id>.scope, /docker/<id>, libpod-<id>.scope, cri-containerd-<id>.scope, ...)
+func ContainerID(pid int) string {
+	data, err := Snapshot().Cgroup(pid)
+	if err != nil {
+		return ""
+	}
+	return containerIDPattern.FindString(string(data))
+}
+
+// ContainerImage returns the image of the container the process runs in, if any
+func ContainerImage(pid int) string {
+	id := ContainerID(pid)
+	if id == "" {
+		return ""
+	}
+	if info := inspectContainer(id); info != nil {
+		return info.image
+	}
+	return ""
+}
+
+// ContainerRestartCount returns how often docker or podman has restarted the
+// container of a process under its restart policy
+func ContainerRestartCount(pid int) (count int, runtime string, ok bool) {
+	id := ContainerID(pid)
+	if id == "" {
+		return 0, "", false
+	}
+	info := inspectContainer(id)
+	if info == nil || info.restartCount < 0 {
+		return 0, "", false
+	}
+	return info.restartCount, info.runtime, true
+}
+
+// ResolveContainerID turns a container name or ID prefix into a full container ID
+// using the docker or podman CLI. A full ID is taken as is, for containers of
+// other runtimes; anything else neither knows is an error rather than a guess.
+func ResolveContainerID(nameOrID string) (string, error) {
+	if info := inspectContainer(nameOrID); info != nil {
+		return info.id, nil
+	}
+	if len(nameOrID) == 64 && containerIDPattern.MatchString(nameOrID) {
+		return nameOrID, nil
+	}
+	return "", fmt.Errorf("no docker or podman container %q", nameOrID)
+}
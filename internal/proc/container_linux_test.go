@@ -0,0 +1,100 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeRuntimes puts docker and podman scripts on PATH, each logging its
+// calls next to itself
+func fakeRuntimes(t *testing.T, docker, podman string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range map[string]string{"docker": docker, "podman": podman} {
+		script := "#!/bin/sh\necho \"$@\" >> " + filepath.Join(dir, name+".log") + "\n" + body + "\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func TestInspectContainer(t *testing.T) {
+	const id = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	defer func(d time.Duration) { containerInspectTimeout = d }(containerInspectTimeout)
+	containerInspectTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { inspectCache = make(map[string]*containerInfo) })
+
+	// A hung docker daemon gives way to podman within the timeout
+	dir := fakeRuntimes(t, "exec /bin/sleep 10", `[ "$4" = cache ] || exit 1; printf '`+id+`\tdocker.io/library/redis:7\t0\n'`)
+	start := time.Now()
+	info := inspectContainer("cache")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("inspectContainer() took %v with a hung docker", elapsed)
+	}
+	if info == nil || info.runtime != "podman" || info.id != id || info.image != "docker.io/library/redis:7" {
+		t.Fatalf("inspectContainer() = %+v", info)
+	}
+
+	// Later lookups, by name or by the full ID, are answered from the first
+	if got := inspectContainer(id); got != info {
+		t.Errorf("inspectContainer(id) = %+v, want the cached result", got)
+	}
+	if got := inspectContainer("cache"); got != info {
+		t.Errorf("inspectContainer(name) = %+v, want the cached result", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "podman.log"))
+	if err != nil || string(data) != "inspect --format {{.Id}}\t{{.Config.Image}}\t{{.RestartCount}} cache\n" {
+		t.Errorf("podman calls = %q, %v", data, err)
+	}
+
+	if got := inspectContainer("missing"); got != nil {
+		t.Errorf("inspectContainer(missing) = %+v, want nil", got)
+	}
+}
+
+func TestResolveContainerID(t *testing.T) {
+	const id = "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	t.Cleanup(func() { inspectCache = make(map[string]*containerInfo) })
+	fakeRuntimes(t, `[ "$4" = web ] || exit 1; printf '`+id+`\tnginx:1.27\t4\n'`, "exit 125")
+
+	if got, err := ResolveContainerID("web"); err != nil || got != id {
+		t.Errorf("ResolveContainerID(web) = %q, %v, want %q", got, err, id)
+	}
+	// An unknown name is not matched as an ID prefix
+	if got, err := ResolveContainerID("fedcba98"); err == nil {
+		t.Errorf("ResolveContainerID(fedcba98) = %q, want an error", got)
+	}
+	// A full ID may belong to a runtime without a CLI witr asks
+	const other = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	if got, err := ResolveContainerID(other); err != nil || got != other {
+		t.Errorf("ResolveContainerID(full ID) = %q, %v, want it unchanged", got, err)
+	}
+}
+
+func TestContainerRestartCount(t *testing.T) {
+	const id = "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"
+	t.Cleanup(func() { inspectCache = make(map[string]*containerInfo) })
+	fakeRuntimes(t, "exit 1", `printf '`+id+`\tnginx:1.27\t4\n'`)
+
+	root := t.TempDir()
+	writeFakeProc(t, root, 42, 1, "nginx")
+	if err := os.WriteFile(filepath.Join(root, "42", "cgroup"), []byte("0::/machine.slice/libpod-"+id+".scope\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(s *Table) { snapshot = s }(snapshot)
+	snapshot = NewTable(root)
+
+	if n, runtime, ok := ContainerRestartCount(42); !ok || n != 4 || runtime != "podman" {
+		t.Errorf("ContainerRestartCount() = %d, %q, %v, want 4 from podman", n, runtime, ok)
+	}
+	// The image comes from the same inspect
+	if got := ContainerImage(42); got != "nginx:1.27" {
+		t.Errorf("ContainerImage() = %q", got)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package proc
+
+// ContainerImage returns the image of the container the process runs in, if any
+func ContainerImage(pid int) string {
+	return ""
+}
+
+// ContainerRestartCount asks docker or podman (Linux only)
+func ContainerRestartCount(pid int) (count int, runtime string, ok bool) {
+	return 0, "", false
+}
@@ -0,0 +1,256 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ReadFileDescriptors classifies the open file descriptors of a process.
+// Sockets are resolved against the process's /proc/<pid>/net tables and
+// pipes are matched to the other processes holding the same pipe.
+func ReadFileDescriptors(pid int) []model.FileDescriptor {
+	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil
+	}
+
+	var fds []model.FileDescriptor
+	pipes := make(map[string]bool)
+	hasSockets := false
+	for _, e := range entries {
+		n, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		link, err := os.Readlink(filepath.Join(fdDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		fd := classifyFD(n, link, filepath.Join(fdDir, e.Name()))
+		switch fd.Category {
+		case model.FDPipe:
+			pipes[linkInode(link)] = true
+			fd.Detail = pipeEnd(pid, n)
+		case model.FDSocket:
+			hasSockets = true
+		}
+		fds = append(fds, fd)
+	}
+	sort.Slice(fds, func(i, j int) bool { return fds[i].FD < fds[j].FD })
+
+	if hasSockets {
+		sockets := readSocketTable(pid)
+		for i := range fds {
+			if fds[i].Category != model.FDSocket {
+				continue
+			}
+			if desc, ok := sockets[linkInode(fds[i].Target)]; ok {
+				fds[i].Detail = desc
+			}
+		}
+	}
+
+	if len(pipes) > 0 {
+		peers := findPipePeers(pid, pipes)
+		for i := range fds {
+			if fds[i].Category == model.FDPipe {
+				fds[i].Peers = peers[linkInode(fds[i].Target)]
+			}
+		}
+	}
+
+	return fds
+}
+
+// classifyFD assigns a category from the fd link target, stat-ing paths to
+// tell files, directories and devices apart
+func classifyFD(n int, link, fdPath string) model.FileDescriptor {
+	fd := model.FileDescriptor{FD: n, Target: link, Category: model.FDOther}
+
+	switch {
+	case strings.HasPrefix(link, "socket:["):
+		fd.Category = model.FDSocket
+	case strings.HasPrefix(link, "pipe:["):
+		fd.Category = model.FDPipe
+	case strings.HasPrefix(link, "anon_inode:"):
+		fd.Category = model.FDAnon
+		fd.Detail = anonInodeKind(link)
+	case strings.HasPrefix(link, "/"):
+		fd.Category = model.FDFile
+		if strings.HasSuffix(link, " (deleted)") {
+			fd.Detail = "deleted"
+		}
+		if info, err := os.Stat(fdPath); err == nil {
+			switch {
+			case info.IsDir():
+				fd.Category = model.FDDir
+			case info.Mode()&os.ModeDevice != 0:
+				fd.Category = model.FDDevice
+			}
+		} else if strings.HasPrefix(link, "/dev/") {
+			fd.Category = model.FDDevice
+		}
+	}
+	return fd
+}
+
+// anonInodeKind names an anonymous inode, e.g. "anon_inode:[eventpoll]" -> "epoll"
+func anonInodeKind(link string) string {
+	kind := strings.Trim(strings.TrimPrefix(link, "anon_inode:"), "[]")
+	if kind == "eventpoll" {
+		return "epoll"
+	}
+	return kind
+}
+
+// linkInode extracts the inode from "socket:[123]" / "pipe:[123]"
+func linkInode(link string) string {
+	start := strings.IndexByte(link, '[')
+	end := strings.IndexByte(link, ']')
+	if start == -1 || end <= start {
+		return ""
+	}
+	return link[start+1 : end]
+}
+
+// pipeEnd reports which end of a pipe an fd is, from the fdinfo access mode
+func pipeEnd(pid, fd int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%d", pid, fd))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		v, ok := strings.CutPrefix(line, "flags:")
+		if !ok {
+			continue
+		}
+		flags, err := strconv.ParseUint(strings.TrimSpace(v), 8, 64)
+		if err != nil {
+			return ""
+		}
+		if flags&0x3 == 0 {
+			return "read end"
+		}
+		return "write end"
+	}
+	return ""
+}
+
+// findPipePeers scans every other process for fds on the given pipe inodes
+func findPipePeers(pid int, pipes map[string]bool) map[string][]model.FDPeer {
+	peers := make(map[string][]model.FDPeer)
+	table := Snapshot()
+	for _, other := range table.Scan("fd") {
+		if other == pid {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, link := range table.FDLinks(other) {
+			if !strings.HasPrefix(link, "pipe:[") {
+				continue
+			}
+			inode := linkInode(link)
+			if !pipes[inode] || seen[inode] {
+				continue
+			}
+			seen[inode] = true
+			peers[inode] = append(peers[inode], model.FDPeer{PID: other, Command: readComm(other)})
+		}
+	}
+	return peers
+}
+
+func readComm(pid int) string {
+	return Snapshot().Comm(pid)
+}
+
+// readSocketTable describes every socket visible in the network namespace of
+// pid, keyed by inode
+func readSocketTable(pid int) map[string]string {
+	sockets := make(map[string]string)
+	netDir := "/proc/" + strconv.Itoa(pid) + "/net"
+
+	for _, t := range []struct {
+		file  string
+		proto string
+		ipv6  bool
+	}{
+		{"tcp", "TCP", false},
+		{"tcp6", "TCP", true},
+		{"udp", "UDP", false},
+		{"udp6", "UDP", true},
+		{"raw", "RAW", false},
+		{"raw6", "RAW", true},
+	} {
+		forEachNetLine(filepath.Join(netDir, t.file), func(fields []string) {
+			if len(fields) < 10 {
+				return
+			}
+			sockets[fields[9]] = describeInetSocket(t.proto, fields[1], fields[2], fields[3], t.ipv6)
+		})
+	}
+
+	forEachNetLine(filepath.Join(netDir, "unix"), func(fields []string) {
+		if len(fields) < 7 {
+			return
+		}
+		desc := "UNIX"
+		if len(fields) >= 8 {
+			desc += " " + fields[7]
+		} else {
+			desc += " (unnamed)"
+		}
+		sockets[fields[6]] = desc
+	})
+
+	forEachNetLine(filepath.Join(netDir, "netlink"), func(fields []string) {
+		if len(fields) < 10 {
+			return
+		}
+		sockets[fields[9]] = "NETLINK"
+	})
+
+	return sockets
+}
+
+// describeInetSocket formats a /proc/net/{tcp,udp,raw}* entry, e.g.
+// "TCP 10.0.0.1:5432 -> 10.0.0.9:40112 (ESTABLISHED)"
+func describeInetSocket(proto, local, remote, state string, ipv6 bool) string {
+	lip, lport := parseAddr(local, ipv6)
+	rip, rport := parseAddr(remote, ipv6)
+
+	desc := proto + " " + net.JoinHostPort(lip, strconv.Itoa(lport))
+	if rport != 0 {
+		desc += " -> " + net.JoinHostPort(rip, strconv.Itoa(rport))
+	}
+	if proto == "TCP" {
+		st, _ := strconv.ParseInt(state, 16, 64)
+		desc += " (" + mapTCPState(int(st)) + ")"
+	}
+	return desc
+}
+
+func forEachNetLine(path string, fn func(fields []string)) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		fn(strings.Fields(scanner.Text()))
+	}
+}
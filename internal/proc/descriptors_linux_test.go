@@ -0,0 +1,49 @@
+//go:build linux
+
+package proc
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestClassifyFD(t *testing.T) {
+	tests := []struct {
+		link     string
+		category model.FDCategory
+		detail   string
+	}{
+		{link: "socket:[12345]", category: model.FDSocket},
+		{link: "pipe:[678]", category: model.FDPipe},
+		{link: "anon_inode:[eventpoll]", category: model.FDAnon, detail: "epoll"},
+		{link: "anon_inode:inotify", category: model.FDAnon, detail: "inotify"},
+		{link: "/nonexistent/witr/app.log (deleted)", category: model.FDFile, detail: "deleted"},
+		{link: "/dev/nonexistent-witr", category: model.FDDevice},
+		{link: "net:[4026531840]", category: model.FDOther},
+	}
+	for _, tt := range tests {
+		fd := classifyFD(3, tt.link, "/nonexistent/fd/3")
+		if fd.Category != tt.category || fd.Detail != tt.detail {
+			t.Errorf("classifyFD(%q) = %s/%q, want %s/%q", tt.link, fd.Category, fd.Detail, tt.category, tt.detail)
+		}
+	}
+}
+
+func TestDescribeInetSocket(t *testing.T) {
+	tests := []struct {
+		proto, local, remote, state string
+		ipv6                        bool
+		want                        string
+	}{
+		{"TCP", "0100007F:1F90", "00000000:0000", "0A", false, "TCP 127.0.0.1:8080 (LISTEN)"},
+		{"TCP", "0100007F:1F90", "0500000A:C8F0", "01", false, "TCP 127.0.0.1:8080 -> 10.0.0.5:51440 (ESTABLISHED)"},
+		{"UDP", "00000000:0035", "00000000:0000", "07", false, "UDP 0.0.0.0:53"},
+		{"TCP", "00000000000000000000000001000000:0016", "00000000000000000000000000000000:0000", "0A", true, "TCP [::1]:22 (LISTEN)"},
+	}
+	for _, tt := range tests {
+		if got := describeInetSocket(tt.proto, tt.local, tt.remote, tt.state, tt.ipv6); got != tt.want {
+			t.Errorf("describeInetSocket(%s %s %s) = %q, want %q", tt.proto, tt.local, tt.remote, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ReadFileDescriptors classifies the open file descriptors of a process (Linux only)
+func ReadFileDescriptors(pid int) []model.FileDescriptor {
+	return nil
+}
@@ -104,21 +104,7 @@ func ReadExtendedInfo(pid int) (model.MemoryInfo, model.IOStats, []string, int,
 	}
 
 	// Find child processes
-	if procEntries, err := os.ReadDir("/proc"); err == nil {
-		for _, entry := range procEntries {
-			if childPID, err := strconv.Atoi(entry.Name()); err == nil {
-				if statData, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", childPID)); err == nil {
-					fields := strings.Fields(string(statData))
-					if len(fields) > 3 {
-						ppid, _ := strconv.Atoi(fields[3])
-						if ppid == pid {
-							children = append(children, childPID)
-						}
-					}
-				}
-			}
-		}
-	}
+	children = append(children, Snapshot().Children(pid)...)
 
 	// Get thread count from /proc/[pid]/status
 	if statusData, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid)); err == nil {
@@ -11,8 +11,9 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-// GetFileContext returns file descriptor and lock info for a process
-func GetFileContext(pid int) *model.FileContext {
+// GetFileContext returns file descriptor and lock info for a process. The
+// locks are only looked up with detail.
+func GetFileContext(pid int, detail bool) *model.FileContext {
 	ctx := &model.FileContext{}
 
 	// Get open file count
@@ -21,7 +22,9 @@ func GetFileContext(pid int) *model.FileContext {
 	ctx.FileLimit = fileLimit
 
 	// Get locked files
-	ctx.LockedFiles = getLockedFiles(pid)
+	if detail {
+		ctx.LockedFiles = getLockedFiles(pid)
+	}
 
 	// Only return if we have meaningful data to show
 	// Show if: high file usage (>50% of limit) or has locks
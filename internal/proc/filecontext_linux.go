@@ -2,14 +2,393 @@
 
 package proc
 
-import "github.com/pranshuparmar/witr/pkg/model"
-
-// GetFileContext returns file descriptor and lock info for a process
-// Linux implementation - TODO: implement using /proc/<pid>/fd and /proc/locks
-func GetFileContext(pid int) *model.FileContext {
-	// Linux implementation could:
-	// - Count /proc/<pid>/fd entries for open files
-	// - Read /proc/<pid>/limits for file limit
-	// - Parse /proc/locks for file locks
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fileID identifies an inode on a device by major/minor number
+type fileID struct {
+	major, minor uint32
+	inode        uint64
+}
+
+// fileLock is a single /proc/locks entry
+type fileLock struct {
+	kind   string // POSIX, FLOCK, OFDLCK
+	access string // READ, WRITE
+	pid    int
+	id     fileID
+}
+
+// inotifyWatch is a single watch from /proc/<pid>/fdinfo of an inotify fd
+type inotifyWatch struct {
+	id fileID
+}
+
+// mountPoint is a single /proc/<pid>/mountinfo entry
+type mountPoint struct {
+	major, minor uint32
+	root         string // path of the mount's root within its filesystem
+	path         string
+}
+
+// GetFileContext returns file descriptor and lock info for a process. The
+// locks and inotify watches are only looked up with detail.
+func GetFileContext(pid int, detail bool) *model.FileContext {
+	ctx := &model.FileContext{}
+
+	fds := readFDTargets(pid)
+	ctx.OpenFiles = len(fds)
+	ctx.FileLimit = readFileLimit(pid)
+
+	if detail {
+		ctx.LockedFiles = getLockedFiles(pid, fds)
+		ctx.WatchedDirs = getWatchedDirs(pid, fds)
+	}
+
+	// Only return if we have meaningful data to show
+	// Show if: high file usage (>50% of limit), locks or watches
+	if len(ctx.LockedFiles) > 0 || len(ctx.WatchedDirs) > 0 {
+		return ctx
+	}
+
+	if ctx.FileLimit > 0 && ctx.OpenFiles > 0 {
+		usagePercent := float64(ctx.OpenFiles) / float64(ctx.FileLimit) * 100
+		if usagePercent > 50 {
+			return ctx
+		}
+	}
+
 	return nil
 }
+
+// readFDTargets returns the link target of every open fd, keyed by fd number
+func readFDTargets(pid int) map[string]string {
+	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil
+	}
+	targets := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if link, err := os.Readlink(filepath.Join(fdDir, e.Name())); err == nil {
+			targets[e.Name()] = link
+		}
+	}
+	return targets
+}
+
+// readFileLimit returns the soft "Max open files" limit, 0 if unlimited or unknown
+func readFileLimit(pid int) int {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", pid))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 4 {
+			limit, _ := strconv.Atoi(fields[3])
+			return limit
+		}
+	}
+	return 0
+}
+
+// statFileID returns the device and inode of a path (following symlinks).
+// It gives up after readTimeout, as the path may be on a hung network mount.
+func statFileID(path string) (fileID, error) {
+	return timed(func() (fileID, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fileID{}, err
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fileID{}, os.ErrInvalid
+		}
+		dev := uint64(st.Dev)
+		return fileID{
+			major: uint32((dev>>8)&0xfff | (dev>>32)&^0xfff),
+			minor: uint32(dev&0xff | (dev>>12)&^0xff),
+			inode: st.Ino,
+		}, nil
+	})
+}
+
+// parseLocks parses /proc/locks. Lines look like:
+//
+//	1: POSIX  ADVISORY  WRITE 1234 08:01:5678 0 EOF
+//	1: -> FLOCK  ADVISORY  WRITE 4321 08:01:5678 0 EOF
+//
+// where blocked waiters ("->") are skipped.
+func parseLocks(content string) []fileLock {
+	var locks []fileLock
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[1] == "->" {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[4])
+		if err != nil {
+			continue
+		}
+		dev := strings.Split(fields[5], ":")
+		if len(dev) != 3 {
+			continue
+		}
+		major, err1 := strconv.ParseUint(dev[0], 16, 32)
+		minor, err2 := strconv.ParseUint(dev[1], 16, 32)
+		inode, err3 := strconv.ParseUint(dev[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		locks = append(locks, fileLock{
+			kind:   fields[1],
+			access: fields[3],
+			pid:    pid,
+			id:     fileID{major: uint32(major), minor: uint32(minor), inode: inode},
+		})
+	}
+	return locks
+}
+
+// getLockedFiles maps /proc/locks entries owned by pid back to paths via its open fds.
+// OFD locks carry no pid, so they are attributed to whoever has the file open.
+func getLockedFiles(pid int, fds map[string]string) []string {
+	data, err := os.ReadFile("/proc/locks")
+	if err != nil {
+		return nil
+	}
+	locks := parseLocks(string(data))
+	if len(locks) == 0 {
+		return nil
+	}
+
+	paths := make(map[fileID]string)
+	for fd, target := range fds {
+		if !strings.HasPrefix(target, "/") {
+			continue
+		}
+		id, err := statFileID(fmt.Sprintf("/proc/%d/fd/%s", pid, fd))
+		if errors.Is(err, errTimedOut) {
+			Snapshot().MarkIncomplete(pid)
+			break
+		}
+		if err == nil {
+			paths[id] = target
+		}
+	}
+
+	var locked []string
+	for _, l := range locks {
+		if l.pid != pid && l.pid != -1 {
+			continue
+		}
+		path, ok := paths[l.id]
+		if !ok {
+			continue
+		}
+		entry := fmt.Sprintf("%s (%s %s)", path, l.kind, strings.ToLower(l.access))
+		if !slices.Contains(locked, entry) {
+			locked = append(locked, entry)
+		}
+	}
+	slices.Sort(locked)
+	return locked
+}
+
+// parseInotifyFdinfo extracts watches from an inotify fd's fdinfo. Lines look like:
+//
+//	inotify wd:1 ino:1a2b sdev:800001 mask:fc6 ignored_mask:0 ...
+//
+// ino is hex and sdev is the kernel's internal dev_t (major<<20 | minor).
+func parseInotifyFdinfo(content string) []inotifyWatch {
+	var watches []inotifyWatch
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "inotify ") {
+			continue
+		}
+		var w inotifyWatch
+		var haveIno, haveDev bool
+		for _, field := range strings.Fields(line) {
+			if v, ok := strings.CutPrefix(field, "ino:"); ok {
+				ino, err := strconv.ParseUint(v, 16, 64)
+				w.id.inode, haveIno = ino, err == nil
+			} else if v, ok := strings.CutPrefix(field, "sdev:"); ok {
+				sdev, err := strconv.ParseUint(v, 16, 64)
+				w.id.major, w.id.minor, haveDev = uint32(sdev>>20), uint32(sdev&0xfffff), err == nil
+			}
+		}
+		if haveIno && haveDev {
+			watches = append(watches, w)
+		}
+	}
+	return watches
+}
+
+// parseMountinfo parses /proc/<pid>/mountinfo. Lines look like:
+//
+//	36 35 98:0 /mnt1 /mnt/parent rw,noatime master:1 - ext3 /dev/root rw
+//
+// with spaces and other special characters in paths octal-escaped (\040).
+func parseMountinfo(content string) []mountPoint {
+	var mounts []mountPoint
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		majorStr, minorStr, ok := strings.Cut(fields[2], ":")
+		if !ok {
+			continue
+		}
+		major, err1 := strconv.ParseUint(majorStr, 10, 32)
+		minor, err2 := strconv.ParseUint(minorStr, 10, 32)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		mounts = append(mounts, mountPoint{
+			major: uint32(major),
+			minor: uint32(minor),
+			root:  unescapeMountPath(fields[3]),
+			path:  unescapeMountPath(fields[4]),
+		})
+	}
+	return mounts
+}
+
+// unescapeMountPath decodes the \ooo octal escapes of mountinfo paths
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// getWatchedDirs decodes inotify watches held by pid and names them from
+// what the process has at hand: its open files and their directories, its
+// working directory and the mount points of the devices still unnamed.
+// Other watches are reported by inode and the mount their device is on.
+func getWatchedDirs(pid int, fds map[string]string) []string {
+	var watches []inotifyWatch
+	for fd, target := range fds {
+		if target != "anon_inode:inotify" {
+			continue
+		}
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/fdinfo/%s", pid, fd))
+		if err != nil {
+			continue
+		}
+		watches = append(watches, parseInotifyFdinfo(string(data))...)
+	}
+	if len(watches) == 0 {
+		return nil
+	}
+
+	// Paths are stat'ed through the process's own fds and root, so they
+	// are looked up in its mount namespace. After a stat hangs the rest
+	// are skipped, they are likely on the same stuck mount.
+	procDir := fmt.Sprintf("/proc/%d", pid)
+	known := make(map[fileID]string)
+	timedOut := false
+	add := func(statPath, name string) {
+		if timedOut {
+			return
+		}
+		id, err := statFileID(statPath)
+		if errors.Is(err, errTimedOut) {
+			timedOut = true
+			Snapshot().MarkIncomplete(pid)
+			return
+		}
+		if _, seen := known[id]; err == nil && !seen {
+			known[id] = name
+		}
+	}
+	unnamedOn := func(m mountPoint) bool {
+		return slices.ContainsFunc(watches, func(w inotifyWatch) bool {
+			_, named := known[w.id]
+			return !named && w.id.major == m.major && w.id.minor == m.minor
+		})
+	}
+	if cwd, err := os.Readlink(procDir + "/cwd"); err == nil {
+		add(procDir+"/cwd", cwd)
+	}
+	for fd, target := range fds {
+		if !strings.HasPrefix(target, "/") {
+			continue
+		}
+		add(procDir+"/fd/"+fd, target)
+		dir := filepath.Dir(strings.TrimSuffix(target, " (deleted)"))
+		add(filepath.Join(procDir, "root", dir), dir)
+	}
+	var mounts []mountPoint
+	if data, err := os.ReadFile(procDir + "/mountinfo"); err == nil {
+		mounts = parseMountinfo(string(data))
+	}
+	for _, m := range mounts {
+		if unnamedOn(m) {
+			add(filepath.Join(procDir, "root", m.path), m.path)
+		}
+	}
+
+	var dirs []string
+	for _, w := range watches {
+		entry, ok := known[w.id]
+		if !ok {
+			entry = fmt.Sprintf("inode %d on %d:%d", w.id.inode, w.id.major, w.id.minor)
+			if m, ok := mountOf(mounts, w.id); ok {
+				entry = fmt.Sprintf("inode %d under %s", w.id.inode, m.path)
+			}
+		}
+		if !slices.Contains(dirs, entry) {
+			dirs = append(dirs, entry)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+// mountOf returns the mount of the device id is on, preferring one of the
+// whole filesystem over bind mounts of a subdirectory
+func mountOf(mounts []mountPoint, id fileID) (mountPoint, bool) {
+	var found *mountPoint
+	for i, m := range mounts {
+		if m.major != id.major || m.minor != id.minor {
+			continue
+		}
+		if m.root == "/" {
+			return m, true
+		}
+		if found == nil {
+			found = &mounts[i]
+		}
+	}
+	if found == nil {
+		return mountPoint{}, false
+	}
+	return *found, true
+}
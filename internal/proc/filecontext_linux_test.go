@@ -0,0 +1,110 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestParseLocks(t *testing.T) {
+	content := "1: POSIX  ADVISORY  WRITE 1234 08:01:5678 0 EOF\n" +
+		"1: -> POSIX  ADVISORY  WRITE 4321 08:01:5678 0 EOF\n" +
+		"2: FLOCK  ADVISORY  READ 99 fd:00:42 0 EOF\n" +
+		"3: OFDLCK ADVISORY  WRITE -1 103:02:7 0 EOF\n"
+
+	want := []fileLock{
+		{kind: "POSIX", access: "WRITE", pid: 1234, id: fileID{major: 8, minor: 1, inode: 5678}},
+		{kind: "FLOCK", access: "READ", pid: 99, id: fileID{major: 253, minor: 0, inode: 42}},
+		{kind: "OFDLCK", access: "WRITE", pid: -1, id: fileID{major: 259, minor: 2, inode: 7}},
+	}
+	if got := parseLocks(content); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseLocks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseInotifyFdinfo(t *testing.T) {
+	content := "pos:\t0\nflags:\t02004000\nmnt_id:\t15\nino:\t1057\n" +
+		"inotify wd:2 ino:1a2b sdev:800001 mask:fc6 ignored_mask:0 fhandle-bytes:8 fhandle-type:1 f_handle:2b1a0000f0a8b0a6\n" +
+		"inotify wd:1 ino:80 sdev:fd00000 mask:100 ignored_mask:0 fhandle-bytes:8 fhandle-type:1 f_handle:8000000000000000\n"
+
+	want := []inotifyWatch{
+		{id: fileID{major: 8, minor: 1, inode: 0x1a2b}},
+		{id: fileID{major: 253, minor: 0, inode: 0x80}},
+	}
+	if got := parseInotifyFdinfo(content); !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseInotifyFdinfo() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseMountinfo(t *testing.T) {
+	content := "22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n" +
+		"36 22 8:1 /srv/data /mnt/my\\040data rw,noatime master:1 - ext4 /dev/sda1 rw\n" +
+		"40 22 0:45 / /proc rw,nosuid - proc proc rw\n"
+
+	mounts := parseMountinfo(content)
+	want := []mountPoint{
+		{major: 8, minor: 1, root: "/", path: "/"},
+		{major: 8, minor: 1, root: "/srv/data", path: "/mnt/my data"},
+		{major: 0, minor: 45, root: "/", path: "/proc"},
+	}
+	if !reflect.DeepEqual(mounts, want) {
+		t.Fatalf("parseMountinfo() = %+v, want %+v", mounts, want)
+	}
+	if m, ok := mountOf(mounts[1:], fileID{major: 8, minor: 1, inode: 9}); !ok || m.path != "/mnt/my data" {
+		t.Errorf("mountOf(bind mount only) = %+v, %v", m, ok)
+	}
+	if m, ok := mountOf(mounts, fileID{major: 8, minor: 1, inode: 9}); !ok || m.path != "/" {
+		t.Errorf("mountOf() = %+v, %v, want the whole filesystem's mount", m, ok)
+	}
+	if _, ok := mountOf(mounts, fileID{major: 9, minor: 9}); ok {
+		t.Error("mountOf(unknown device) found a mount")
+	}
+}
+
+func TestGetWatchedDirs(t *testing.T) {
+	dir := t.TempDir()
+	unopened := filepath.Join(dir, "unopened")
+	if err := os.Mkdir(unopened, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	held, err := os.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		t.Skipf("inotify unavailable: %v", err)
+	}
+	defer syscall.Close(fd)
+	for _, path := range []string{dir, unopened, "/proc"} {
+		if _, err := syscall.InotifyAddWatch(fd, path, syscall.IN_CREATE); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pid := os.Getpid()
+	dirs := getWatchedDirs(pid, readFDTargets(pid))
+	if !slices.Contains(dirs, dir) {
+		t.Errorf("getWatchedDirs() = %v, want the open directory %s", dirs, dir)
+	}
+	// A mount point is named from mountinfo
+	if !slices.Contains(dirs, "/proc") {
+		t.Errorf("getWatchedDirs() = %v, want the mount point /proc", dirs)
+	}
+	// Nothing names the other one without walking the filesystem
+	var byInode bool
+	for _, d := range dirs {
+		byInode = byInode || strings.HasPrefix(d, "inode ")
+	}
+	if len(dirs) != 3 || !byInode {
+		t.Errorf("getWatchedDirs() = %v, want the unopened directory by inode", dirs)
+	}
+}
@@ -4,6 +4,6 @@ package proc
 
 import "github.com/pranshuparmar/witr/pkg/model"
 
-func GetFileContext(pid int) *model.FileContext {
+func GetFileContext(pid int, detail bool) *model.FileContext {
 	return nil
 }
@@ -0,0 +1,277 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// journalDirs hold the systemd journal files: persistent, then volatile
+var journalDirs = []string{"/var/log/journal", "/run/log/journal"}
+
+// Journal file format, see systemd's "Journal File Format" documentation
+const (
+	journalSignature = "LPKSHHRH"
+
+	journalObjectData  = 1
+	journalObjectEntry = 3
+
+	// Object flags for an xz, lz4 or zstd compressed payload
+	journalCompressed = 1 | 2 | 4
+	// Incompatible header flag for 32-bit item offsets
+	journalCompact = 16
+)
+
+// journalEntry is an entry of the systemd journal. Only fields stored
+// uncompressed are read; journald compresses large ones only.
+type journalEntry struct {
+	time   time.Time
+	fields map[string]string
+}
+
+// readJournal returns the entries of the journal files on disk that have
+// any of the FIELD=value matches, oldest first
+func readJournal(matches ...string) []journalEntry {
+	var entries []journalEntry
+	for _, dir := range journalDirs {
+		for _, pattern := range []string{"*.journal", "*.journal~"} {
+			files, _ := filepath.Glob(filepath.Join(dir, "*", pattern))
+			for _, file := range files {
+				entries = append(entries, readJournalFile(file, matches)...)
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].time.Before(entries[j].time) })
+	return entries
+}
+
+func readJournalFile(file string, matches []string) (entries []journalEntry) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil
+	}
+	defer syscall.Munmap(data)
+
+	// journald may be writing the file; a fault on a page it has not
+	// filled in yet ends the read instead of crashing
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if recover() != nil {
+			entries = nil
+		}
+	}()
+	return parseJournal(data, matches)
+}
+
+// parseJournal walks the objects of a journal file and returns the entries
+// referencing a data object equal to one of matches. journald writes the
+// data objects of an entry before the entry, so one pass finds both.
+func parseJournal(data []byte, matches []string) []journalEntry {
+	le := binary.LittleEndian
+	if len(data) < 104 || string(data[:8]) != journalSignature {
+		return nil
+	}
+	compact := le.Uint32(data[12:])&journalCompact != 0
+	headerSize := le.Uint64(data[88:])
+	end := min(headerSize+le.Uint64(data[96:]), uint64(len(data)))
+
+	payloadStart, itemSize := uint64(64), uint64(16)
+	if compact {
+		payloadStart, itemSize = 72, 4
+	}
+	// object returns the type, flags and bounds of the object at off
+	object := func(off uint64) (byte, byte, uint64, bool) {
+		if off < headerSize || off+16 > end {
+			return 0, 0, 0, false
+		}
+		size := le.Uint64(data[off+8:])
+		if size < 16 || size > end-off {
+			return 0, 0, 0, false
+		}
+		return data[off], data[off+1], off + size, true
+	}
+	payload := func(off uint64) (string, bool) {
+		typ, flags, objEnd, ok := object(off)
+		if !ok || typ != journalObjectData || flags&journalCompressed != 0 || off+payloadStart > objEnd {
+			return "", false
+		}
+		return string(data[off+payloadStart : objEnd]), true
+	}
+	items := func(off, objEnd uint64) []uint64 {
+		var offs []uint64
+		for i := off + 64; i+itemSize <= objEnd; i += itemSize {
+			if compact {
+				offs = append(offs, uint64(le.Uint32(data[i:])))
+			} else {
+				offs = append(offs, le.Uint64(data[i:]))
+			}
+		}
+		return offs
+	}
+
+	want := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		want[m] = true
+	}
+	matched := make(map[uint64]bool)
+	var entries []journalEntry
+	for off := headerSize; ; {
+		typ, _, objEnd, ok := object(off)
+		if !ok {
+			break
+		}
+		switch typ {
+		case journalObjectData:
+			if p, ok := payload(off); ok && want[p] {
+				matched[off] = true
+			}
+		case journalObjectEntry:
+			if objEnd < off+64 {
+				break
+			}
+			refs := items(off, objEnd)
+			if !containsMatched(refs, matched) {
+				break
+			}
+			entry := journalEntry{
+				time:   time.UnixMicro(int64(le.Uint64(data[off+24:]))),
+				fields: make(map[string]string),
+			}
+			for _, ref := range refs {
+				if p, ok := payload(ref); ok {
+					if key, value, ok := strings.Cut(p, "="); ok {
+						entry.fields[key] = value
+					}
+				}
+			}
+			entries = append(entries, entry)
+		}
+		off = (objEnd + 7) &^ 7
+	}
+	return entries
+}
+
+func containsMatched(refs []uint64, matched map[uint64]bool) bool {
+	for _, ref := range refs {
+		if matched[ref] {
+			return true
+		}
+	}
+	return false
+}
+
+// JournalUnitState rebuilds the last state of a unit from what systemd
+// logged about it, for units it no longer knows: transient units and
+// units garbage-collected after they stopped. Nil if nothing was logged.
+func JournalUnitState(unit string) *model.UnitState {
+	return unitStateFromJournal(unit, readJournal("UNIT="+unit, "USER_UNIT="+unit))
+}
+
+var (
+	// "app.service: Main process exited, code=killed, status=9/KILL"
+	journalMainExit = regexp.MustCompile(`Main process exited, code=(\w+), status=(\d+)`)
+	// "app.service: Failed with result 'oom-kill'."
+	journalFailed = regexp.MustCompile(`Failed with result '([\w-]+)'`)
+	// "app.service: Scheduled restart job, restart counter is at 3."
+	journalRestart = regexp.MustCompile(`restart counter is at (\d+)`)
+)
+
+// fromManager reports whether an entry about unit was logged by a systemd
+// manager. UNIT= and USER_UNIT= are fields any process may set, so they
+// are only believed with the trusted fields journald adds itself: PID 1
+// for system units, a user manager (the init.scope of user@<uid>.service,
+// running as that uid) for user units.
+func fromManager(e journalEntry, unit string) bool {
+	if e.fields["UNIT"] == unit && e.fields["_PID"] == "1" {
+		return true
+	}
+	uid := e.fields["_UID"]
+	return e.fields["USER_UNIT"] == unit && uid != "" &&
+		e.fields["_SYSTEMD_UNIT"] == "user@"+uid+".service" &&
+		e.fields["_SYSTEMD_USER_UNIT"] == "init.scope"
+}
+
+// unitStateFromJournal replays systemd's messages about a unit
+func unitStateFromJournal(unit string, entries []journalEntry) *model.UnitState {
+	entries = slices.DeleteFunc(entries, func(e journalEntry) bool { return !fromManager(e, unit) })
+	if len(entries) == 0 {
+		return nil
+	}
+	s := &model.UnitState{Name: unit, FromJournal: true}
+	for _, e := range entries {
+		msg := e.fields["MESSAGE"]
+		if _, rest, ok := strings.Cut(msg, unit+": "); ok {
+			msg = rest
+		}
+		switch {
+		case strings.HasPrefix(msg, "Started "):
+			s.ActiveState, s.SubState, s.Result = "active", "running", ""
+			s.ExecMainStart, s.StateChange = e.time, e.time
+			s.ExecMainCode, s.ExecMainStatus, s.ExecMainExit = "", 0, time.Time{}
+		case journalMainExit.MatchString(msg):
+			m := journalMainExit.FindStringSubmatch(msg)
+			s.ExecMainCode = m[1]
+			s.ExecMainStatus, _ = strconv.Atoi(m[2])
+			s.ExecMainExit = e.time
+		case journalFailed.MatchString(msg):
+			s.Result = journalFailed.FindStringSubmatch(msg)[1]
+			s.ActiveState, s.SubState, s.StateChange = "failed", "failed", e.time
+		case strings.HasPrefix(msg, "Deactivated successfully"):
+			s.Result = "success"
+			s.ActiveState, s.SubState, s.StateChange = "inactive", "dead", e.time
+		case strings.HasPrefix(msg, "Stopped "):
+			if s.Result == "" {
+				s.Result = "success"
+			}
+			s.ActiveState, s.SubState, s.StateChange = "inactive", "dead", e.time
+		case journalRestart.MatchString(msg):
+			s.NRestarts, _ = strconv.Atoi(journalRestart.FindStringSubmatch(msg)[1])
+		}
+	}
+	return s
+}
+
+// JournalUnitOf returns the unit a process ran in, from what it logged to
+// the journal at or after since (so not what an earlier process with the
+// same PID logged). Empty if it logged nothing, or since is zero: then
+// nothing tells the process's lines from an earlier one's.
+func JournalUnitOf(pid int, since time.Time) string {
+	if since.IsZero() {
+		return ""
+	}
+	return unitOfPID(readJournal("_PID="+strconv.Itoa(pid)), since)
+}
+
+func unitOfPID(entries []journalEntry, since time.Time) string {
+	unit := ""
+	for _, e := range entries {
+		if e.time.Before(since) {
+			continue
+		}
+		if u := e.fields["_SYSTEMD_UNIT"]; u != "" {
+			unit = u
+		}
+	}
+	return unit
+}
@@ -0,0 +1,156 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// testJournal builds a journal file the way journald writes one: the data
+// objects of an entry first, then the entry referencing them
+type testJournal struct {
+	buf     []byte
+	compact bool
+	data    map[string]uint64
+}
+
+func newTestJournal(compact bool) *testJournal {
+	j := &testJournal{buf: make([]byte, 256), compact: compact, data: make(map[string]uint64)}
+	copy(j.buf, journalSignature)
+	if compact {
+		binary.LittleEndian.PutUint32(j.buf[12:], journalCompact)
+	}
+	binary.LittleEndian.PutUint64(j.buf[88:], 256)
+	return j
+}
+
+func (j *testJournal) object(typ, flags byte, body []byte) uint64 {
+	off := uint64(len(j.buf))
+	header := make([]byte, 16)
+	header[0], header[1] = typ, flags
+	binary.LittleEndian.PutUint64(header[8:], uint64(16+len(body)))
+	j.buf = append(append(j.buf, header...), body...)
+	for len(j.buf)%8 != 0 {
+		j.buf = append(j.buf, 0)
+	}
+	return off
+}
+
+func (j *testJournal) dataObject(field string, flags byte) uint64 {
+	if off, ok := j.data[field]; ok {
+		return off
+	}
+	fixed := 48
+	if j.compact {
+		fixed = 56
+	}
+	off := j.object(journalObjectData, flags, append(make([]byte, fixed), field...))
+	j.data[field] = off
+	return off
+}
+
+func (j *testJournal) entry(t time.Time, refs ...uint64) {
+	body := make([]byte, 48)
+	binary.LittleEndian.PutUint64(body[8:], uint64(t.UnixMicro()))
+	for _, ref := range refs {
+		if j.compact {
+			body = binary.LittleEndian.AppendUint32(body, uint32(ref))
+		} else {
+			body = binary.LittleEndian.AppendUint64(body, ref)
+			body = binary.LittleEndian.AppendUint64(body, 0)
+		}
+	}
+	j.object(journalObjectEntry, 0, body)
+}
+
+func (j *testJournal) log(t time.Time, fields ...string) {
+	var refs []uint64
+	for _, f := range fields {
+		refs = append(refs, j.dataObject(f, 0))
+	}
+	j.entry(t, refs...)
+}
+
+func (j *testJournal) bytes() []byte {
+	binary.LittleEndian.PutUint64(j.buf[96:], uint64(len(j.buf)-256))
+	return j.buf
+}
+
+func TestParseJournal(t *testing.T) {
+	start := time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC)
+	for _, compact := range []bool{false, true} {
+		j := newTestJournal(compact)
+		j.log(start.Add(-time.Hour), "UNIT=app.service", "_PID=1", "MESSAGE=app.service: Failed with result 'exit-code'.")
+		j.log(start, "UNIT=app.service", "_PID=1", "MESSAGE=Started app.service - My App.")
+		j.log(start.Add(time.Second), "_SYSTEMD_UNIT=app.service", "_PID=4242", "MESSAGE=listening on :8080")
+		j.log(start.Add(time.Minute), "UNIT=other.service", "_PID=1", "MESSAGE=Started other.service.")
+		j.log(start.Add(2*time.Minute), "UNIT=app.service", "_PID=1", "MESSAGE=app.service: Main process exited, code=killed, status=9/KILL")
+		// Anyone can log UNIT=; only PID 1 is believed
+		j.log(start.Add(150*time.Second), "UNIT=app.service", "_PID=4242", "_UID=1000", "MESSAGE=app.service: Failed with result 'exit-code'.")
+		// Large fields are compressed, and left out
+		j.entry(start.Add(3*time.Minute), j.dataObject("UNIT=app.service", 0), j.dataObject("_PID=1", 0),
+			j.dataObject("MESSAGE=app.service: Failed with result 'oom-kill'.", 0), j.dataObject("MESSAGE_DETAIL=xxxx", 2))
+		data := j.bytes()
+
+		entries := parseJournal(data, []string{"UNIT=app.service"})
+		if len(entries) != 5 {
+			t.Fatalf("compact=%v: parseJournal() = %d entries, want 5", compact, len(entries))
+		}
+		if _, ok := entries[4].fields["MESSAGE_DETAIL"]; ok {
+			t.Errorf("compact=%v: compressed field was read", compact)
+		}
+
+		s := unitStateFromJournal("app.service", entries)
+		if s == nil || !s.FromJournal || s.Result != "oom-kill" || s.ActiveState != "failed" ||
+			s.ExecMainCode != "killed" || s.ExecMainStatus != 9 || !s.ExecMainStart.Equal(start) ||
+			!s.ExecMainExit.Equal(start.Add(2*time.Minute)) {
+			t.Errorf("compact=%v: unitStateFromJournal() = %+v", compact, s)
+		}
+
+		byPID := parseJournal(data, []string{"_PID=4242"})
+		if got := unitOfPID(byPID, start); got != "app.service" {
+			t.Errorf("compact=%v: unitOfPID() = %q, want app.service", compact, got)
+		}
+		// Lines logged before the process started were another process's
+		if got := unitOfPID(byPID, start.Add(time.Hour)); got != "" {
+			t.Errorf("compact=%v: unitOfPID(later) = %q, want none", compact, got)
+		}
+	}
+
+	// Without a start time any earlier process with the PID may have logged
+	if got := JournalUnitOf(4242, time.Time{}); got != "" {
+		t.Errorf("JournalUnitOf(no start) = %q, want none", got)
+	}
+
+	// User units are believed from the user's own manager only
+	user := []journalEntry{
+		{start, map[string]string{"USER_UNIT": "app.service", "_UID": "1000", "_SYSTEMD_UNIT": "user@1000.service",
+			"_SYSTEMD_USER_UNIT": "init.scope", "MESSAGE": "Started app.service."}},
+		{start.Add(time.Minute), map[string]string{"USER_UNIT": "app.service", "_UID": "1000", "_SYSTEMD_UNIT": "user@1000.service",
+			"_SYSTEMD_USER_UNIT": "app.service", "MESSAGE": "app.service: Failed with result 'oom-kill'."}},
+		{start.Add(time.Minute), map[string]string{"USER_UNIT": "app.service", "_UID": "1001", "_SYSTEMD_UNIT": "user@1000.service",
+			"_SYSTEMD_USER_UNIT": "init.scope", "MESSAGE": "app.service: Failed with result 'oom-kill'."}},
+	}
+	if s := unitStateFromJournal("app.service", user); s == nil || s.ActiveState != "active" || s.Result != "" {
+		t.Errorf("unitStateFromJournal(user unit) = %+v, want active", s)
+	}
+	if got := unitStateFromJournal("app.service", user[1:]); got != nil {
+		t.Errorf("unitStateFromJournal(not from a manager) = %+v, want nil", got)
+	}
+
+	if got := unitStateFromJournal("gone.service", nil); got != nil {
+		t.Errorf("unitStateFromJournal(no entries) = %+v, want nil", got)
+	}
+	if got := parseJournal([]byte("not a journal"), []string{"UNIT=x"}); got != nil {
+		t.Errorf("parseJournal(garbage) = %v", got)
+	}
+	// A truncated file stops at the last complete object
+	data := newTestJournal(false)
+	data.log(start, "UNIT=app.service", "MESSAGE=Started app.")
+	raw := data.bytes()
+	if got := parseJournal(raw[:len(raw)-10], []string{"UNIT=app.service"}); len(got) != 0 {
+		t.Errorf("parseJournal(truncated) = %v", got)
+	}
+}
@@ -0,0 +1,286 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// subreapers adopt orphaned descendants instead of PID 1 (PR_SET_CHILD_SUBREAPER).
+// The kernel does not expose the flag, so well-known ones are matched by name.
+var subreapers = map[string]bool{
+	"systemd":   true, // systemd --user
+	"tini":      true,
+	"dumb-init": true,
+	"catatonit": true,
+	"s6-svscan": true,
+	"runsvdir":  true,
+}
+
+// lineageShells are the interactive shells a detached job can come from
+var lineageShells = map[string]bool{
+	"bash": true, "zsh": true, "sh": true, "dash": true, "fish": true,
+	"ksh": true, "csh": true, "tcsh": true,
+}
+
+// statIDs are the job-control identifiers and start time from /proc/<pid>/stat
+type statIDs struct {
+	ppid, pgid, sid, ttyNr, tpgid int
+	startTicks                    int64
+}
+
+func readStatIDs(pid int) (statIDs, error) {
+	data, err := Snapshot().Stat(pid)
+	if err != nil {
+		return statIDs{}, err
+	}
+	return parseStatIDs(string(data))
+}
+
+// parseStatIDs reads fields 4-8 and 22 of a stat line. The command is in
+// parentheses and may itself contain spaces and parentheses.
+func parseStatIDs(raw string) (statIDs, error) {
+	end := strings.LastIndex(raw, ")")
+	if end == -1 || end+2 > len(raw) {
+		return statIDs{}, fmt.Errorf("invalid stat format")
+	}
+	fields := strings.Fields(raw[end+2:])
+	if len(fields) < 6 {
+		return statIDs{}, fmt.Errorf("invalid stat format")
+	}
+	var ids statIDs
+	for i, dst := range []*int{&ids.ppid, &ids.pgid, &ids.sid, &ids.ttyNr, &ids.tpgid} {
+		*dst, _ = strconv.Atoi(fields[i+1])
+	}
+	if len(fields) > 19 {
+		ids.startTicks, _ = strconv.ParseInt(fields[19], 10, 64)
+	}
+	return ids, nil
+}
+
+// ignoresSIGHUP reports whether SIGHUP (signal 1) is in the SigIgn mask
+func ignoresSIGHUP(pid int) bool {
+	data, err := Snapshot().Status(pid)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "SigIgn:"); ok {
+			mask, err := strconv.ParseUint(strings.TrimSpace(v), 16, 64)
+			return err == nil && mask&1 != 0
+		}
+	}
+	return false
+}
+
+// ReadLineage works out whether the last process of the ancestry was
+// detached from whoever launched it. Evidence comes from job control
+// (session and process group leadership), the adopting parent, the audit
+// login session, the SIGHUP disposition and a nohup.out next to it.
+func ReadLineage(ancestry []model.Process) *model.Lineage {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target, parent := ancestry[len(ancestry)-1], ancestry[len(ancestry)-2]
+	ids, err := readStatIDs(target.PID)
+	if err != nil {
+		return nil
+	}
+	parentIDs, _ := readStatIDs(parent.PID)
+
+	l := &model.Lineage{}
+	evidence := func(format string, args ...any) {
+		l.Evidence = append(l.Evidence, fmt.Sprintf(format, args...))
+	}
+	addMethod := func(m string) {
+		l.Methods = append(l.Methods, m)
+	}
+
+	// Shells export the path of the program they run as $_, which names
+	// wrappers such as nohup and setsid even after the shell has gone
+	launcher := ""
+	for _, e := range target.Env {
+		if v, ok := strings.CutPrefix(e, "_="); ok {
+			launcher = v
+		}
+	}
+
+	leaderAlive := true
+	leaderComm := ""
+	if ids.sid != target.PID && ids.sid > 0 {
+		leaderComm = readComm(ids.sid)
+		leaderAlive = leaderComm != ""
+	}
+
+	// Launched from an interactive shell or login
+	switch {
+	case lineageShells[parent.Command]:
+		l.FromShell = true
+	case lineageShells[leaderComm]:
+		l.FromShell = true
+		evidence("session leader is %s (pid %d)", leaderComm, ids.sid)
+	case target.Login != nil && parent.Login == nil:
+		l.FromShell = true
+		evidence("belongs to login session of %s but its parent does not", target.Login.User)
+	case launcher != "":
+		l.FromShell = true
+		evidence("environment set by a shell (_=%s)", launcher)
+	}
+
+	// Adopted by init or a subreaper after its parent exited
+	adopter := parent.PID == 1 || subreapers[parent.Command]
+	if adopter {
+		sessionFromElsewhere := ids.sid != target.PID && ids.sid != parentIDs.sid
+		if l.FromShell || sessionFromElsewhere {
+			l.Reparented = true
+			l.Adopter = fmt.Sprintf("%s (pid %d)", parent.Command, parent.PID)
+			evidence("parent exited, adopted by %s", l.Adopter)
+		}
+		if sessionFromElsewhere && !leaderAlive {
+			evidence("session leader %d has exited", ids.sid)
+			if !l.FromShell {
+				addMethod("double fork")
+			}
+		}
+	}
+
+	hupIgnored := ignoresSIGHUP(target.PID)
+	nohupOut := ""
+	if target.WorkingDir != "" && target.WorkingDir != "unknown" && Snapshot().Exists(target.PID, "cwd/nohup.out") {
+		nohupOut = filepath.Join(target.WorkingDir, "nohup.out")
+	}
+	if hupIgnored {
+		evidence("SIGHUP ignored")
+	}
+	if nohupOut != "" {
+		evidence("%s exists", nohupOut)
+	}
+	if (hupIgnored && (l.FromShell || nohupOut != "")) || filepath.Base(launcher) == "nohup" {
+		addMethod("nohup")
+	}
+
+	if ids.sid == target.PID && ((l.FromShell && adopter) || filepath.Base(launcher) == "setsid") {
+		evidence("leads its own session")
+		addMethod("setsid")
+	}
+
+	// "cmd &" in a shell that is still around: own process group, not the
+	// terminal's foreground group
+	if lineageShells[parent.Command] && ids.pgid == target.PID && ids.pgid != parentIDs.pgid &&
+		ids.ttyNr != 0 && ids.tpgid != ids.pgid {
+		evidence("not in the terminal's foreground process group")
+		addMethod("background job")
+	}
+
+	if !l.Reparented && len(l.Methods) == 0 {
+		return nil
+	}
+	if l.Reparented {
+		l.ProbableParent = findProbableParent(target, ids, parent.PID)
+	}
+	return l
+}
+
+// parentCandidate is a process that may have launched a reparented one
+type parentCandidate struct {
+	pid      int
+	ids      statIDs
+	score    int
+	evidence []string
+}
+
+// findProbableParent looks for the launcher of a reparented process among
+// the processes still running: the leader of its session or process group,
+// then processes that share its session, audit login session or cgroup and
+// were started before it, preferring the most recently started. When the
+// session leader has exited, only its PID is left to report.
+func findProbableParent(target model.Process, ids statIDs, adopterPID int) *model.ProbableParent {
+	table := Snapshot()
+	auditSession := 0
+	if target.Login != nil {
+		auditSession = target.Login.SessionID
+	}
+	cgroup, _ := table.Cgroup(target.PID)
+
+	var best *parentCandidate
+	for _, pid := range table.Scan("stat") {
+		if pid == target.PID || pid == adopterPID || pid == 1 {
+			continue
+		}
+		cids, err := readStatIDs(pid)
+		if err != nil || cids.startTicks > ids.startTicks || cids.ppid == target.PID {
+			continue
+		}
+		// Other orphans of the same adopter are siblings, not launchers
+		if cids.ppid == adopterPID && pid != ids.sid && pid != ids.pgid {
+			continue
+		}
+
+		c := parentCandidate{pid: pid, ids: cids}
+		related := false
+		if pid == ids.sid {
+			c.score += 3
+			c.evidence = append(c.evidence, fmt.Sprintf("leads session %d the process belongs to", ids.sid))
+			related = true
+		} else if cids.sid == ids.sid {
+			c.score += 2
+			c.evidence = append(c.evidence, fmt.Sprintf("in the same session (%d)", ids.sid))
+			related = true
+		}
+		if pid == ids.pgid {
+			c.score += 2
+			c.evidence = append(c.evidence, fmt.Sprintf("leads process group %d the process belongs to", ids.pgid))
+			related = true
+		}
+		if auditSession != 0 {
+			if login := readLogin(pid); login != nil && login.SessionID == auditSession {
+				c.score += 2
+				c.evidence = append(c.evidence, fmt.Sprintf("same login session %d (%s)", auditSession, login.User))
+				related = true
+			}
+		}
+		if !related {
+			continue
+		}
+		if len(cgroup) > 0 {
+			if cg, err := table.Cgroup(pid); err == nil && string(cg) == string(cgroup) {
+				c.score++
+				c.evidence = append(c.evidence, "same cgroup")
+			}
+		}
+		if lineageShells[readComm(pid)] {
+			c.score++
+			c.evidence = append(c.evidence, "is a shell")
+		}
+
+		if best == nil || c.score > best.score || (c.score == best.score && c.ids.startTicks > best.ids.startTicks) {
+			best = &c
+		}
+	}
+
+	if best == nil {
+		if ids.sid != target.PID && ids.sid > 0 && readComm(ids.sid) == "" {
+			return &model.ProbableParent{
+				PID:      ids.sid,
+				Evidence: []string{fmt.Sprintf("created session %d the process belongs to, has exited", ids.sid)},
+			}
+		}
+		return nil
+	}
+
+	p := &model.ProbableParent{PID: best.pid, Alive: true, Command: readComm(best.pid), Evidence: best.evidence}
+	p.Cmdline = table.Cmdline(best.pid)
+	gap := time.Duration(ids.startTicks-best.ids.startTicks) * time.Second / ticksPerSecond()
+	if gap < time.Second {
+		p.Evidence = append(p.Evidence, "started just before it")
+	} else {
+		p.Evidence = append(p.Evidence, "started "+gap.Round(time.Second).String()+" before it")
+	}
+	return p
+}
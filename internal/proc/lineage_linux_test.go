@@ -0,0 +1,341 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseStatIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    statIDs
+		wantErr bool
+	}{
+		{
+			name: "background job",
+			raw:  "4242 (sleep) S 4100 4242 4100 34816 4300 4194304 98 0 0 0 0 0 0 0 20 0 1 0 123456 5672960 128 18446744073709551615",
+			want: statIDs{ppid: 4100, pgid: 4242, sid: 4100, ttyNr: 34816, tpgid: 4300, startTicks: 123456},
+		},
+		{
+			name: "command with spaces and parentheses",
+			raw:  "77 (tmux: server (x)) S 1 77 77 0 -1 4194624 0 0",
+			want: statIDs{ppid: 1, pgid: 77, sid: 77, ttyNr: 0, tpgid: -1},
+		},
+		{
+			name:    "truncated",
+			raw:     "77 (sleep) S 1 77",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStatIDs(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStatIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseStatIDs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// lineageProc is a process of a synthetic /proc for the lineage tests
+type lineageProc struct {
+	pid, ppid, pgid, sid, tty, tpgid int
+	start                            int64
+	comm                             string
+	cgroup                           string
+	session                          int // audit session, 0 for none
+	sigIgn                           uint64
+	cwd                              string // working directory, none if empty
+}
+
+// useFakeProc makes the shared process table read procs from a synthetic
+// /proc for the rest of the test
+func useFakeProc(t *testing.T, procs ...lineageProc) {
+	t.Helper()
+	root := t.TempDir()
+	for _, p := range procs {
+		dir := filepath.Join(root, strconv.Itoa(p.pid))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		cgroup := p.cgroup
+		if cgroup == "" {
+			cgroup = "/user.slice/user-1000.slice/session-1.scope"
+		}
+		files := map[string]string{
+			"stat": fmt.Sprintf("%d (%s) S %d %d %d %d %d 4194560 0 0 0 0 1 2 0 0 20 0 1 0 %d 0 0\n",
+				p.pid, p.comm, p.ppid, p.pgid, p.sid, p.tty, p.tpgid, p.start),
+			"status":  fmt.Sprintf("Name:\t%s\nSigIgn:\t%016x\n", p.comm, p.sigIgn),
+			"cmdline": p.comm + "\x00",
+			"cgroup":  "0::" + cgroup + "\n",
+		}
+		if p.session != 0 {
+			files["loginuid"] = "0"
+			files["sessionid"] = strconv.Itoa(p.session)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if p.cwd != "" {
+			if err := os.Symlink(p.cwd, filepath.Join(dir, "cwd")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	old := snapshot
+	snapshot = NewTable(root)
+	t.Cleanup(func() { snapshot = old })
+}
+
+func TestFindProbableParent(t *testing.T) {
+	// The target is pid 500, adopted by init and started at tick 10000
+	target := lineageProc{pid: 500, ppid: 1, pgid: 500, sid: 100, start: 10000, comm: "worker"}
+	initProc := lineageProc{pid: 1, sid: 1, pgid: 1, start: 1, comm: "init", cgroup: "/init.scope"}
+
+	tests := []struct {
+		name         string
+		target       lineageProc
+		others       []lineageProc
+		wantPID      int
+		wantAlive    bool
+		wantEvidence []string
+	}{
+		{
+			name:   "session leader",
+			target: target,
+			others: []lineageProc{
+				{pid: 100, ppid: 90, pgid: 100, sid: 100, start: 9500, comm: "bash"},
+				{pid: 200, ppid: 1, pgid: 200, sid: 200, start: 9900, comm: "bash", cgroup: "/system.slice/ssh.service"},
+			},
+			wantPID:      100,
+			wantAlive:    true,
+			wantEvidence: []string{"leads session 100 the process belongs to", "same cgroup", "is a shell", "started 5s before it"},
+		},
+		{
+			name:   "process group leader outranks the rest of the session",
+			target: lineageProc{pid: 500, ppid: 1, pgid: 300, sid: 100, start: 10000, comm: "worker"},
+			others: []lineageProc{
+				{pid: 300, ppid: 90, pgid: 300, sid: 100, start: 9000, comm: "make"},
+				{pid: 310, ppid: 90, pgid: 310, sid: 100, start: 9990, comm: "vim"},
+			},
+			wantPID:      300,
+			wantAlive:    true,
+			wantEvidence: []string{"in the same session (100)", "leads process group 300 the process belongs to", "same cgroup", "started 10s before it"},
+		},
+		{
+			name:   "audit login session",
+			target: lineageProc{pid: 500, ppid: 1, pgid: 500, sid: 500, start: 10000, comm: "worker", session: 7},
+			others: []lineageProc{
+				{pid: 400, ppid: 90, pgid: 400, sid: 400, start: 9000, comm: "sshd", cgroup: "/system.slice/ssh.service", session: 7},
+				{pid: 410, ppid: 90, pgid: 410, sid: 410, start: 9800, comm: "bash", session: 8},
+			},
+			wantPID:      400,
+			wantAlive:    true,
+			wantEvidence: []string{"same login session 7 (root)", "started 10s before it"},
+		},
+		{
+			name:   "most recently started wins a tie, later processes are left out",
+			target: target,
+			others: []lineageProc{
+				{pid: 110, ppid: 90, pgid: 110, sid: 100, start: 8000, comm: "tail"},
+				{pid: 120, ppid: 90, pgid: 120, sid: 100, start: 9700, comm: "less"},
+				{pid: 130, ppid: 90, pgid: 130, sid: 100, start: 10500, comm: "top"},
+			},
+			wantPID:      120,
+			wantAlive:    true,
+			wantEvidence: []string{"in the same session (100)", "same cgroup", "started 3s before it"},
+		},
+		{
+			name:   "siblings and children are not launchers",
+			target: target,
+			others: []lineageProc{
+				{pid: 140, ppid: 1, pgid: 140, sid: 100, start: 9000, comm: "orphan"},
+				{pid: 150, ppid: 500, pgid: 500, sid: 100, start: 9000, comm: "child"},
+			},
+			wantPID:      100,
+			wantEvidence: []string{"created session 100 the process belongs to, has exited"},
+		},
+		{
+			name:   "nothing related is running",
+			target: lineageProc{pid: 500, ppid: 1, pgid: 500, sid: 500, start: 10000, comm: "worker"},
+			others: []lineageProc{
+				{pid: 200, ppid: 1, pgid: 200, sid: 200, start: 9900, comm: "bash"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeProc(t, append([]lineageProc{initProc, tt.target}, tt.others...)...)
+			ids, err := readStatIDs(tt.target.pid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proc := model.Process{PID: tt.target.pid, Login: readLogin(tt.target.pid)}
+
+			got := findProbableParent(proc, ids, 1)
+			if tt.wantPID == 0 {
+				if got != nil {
+					t.Fatalf("findProbableParent() = %+v, want none", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("findProbableParent() = nil")
+			}
+			if got.PID != tt.wantPID || got.Alive != tt.wantAlive || !slices.Equal(got.Evidence, tt.wantEvidence) {
+				t.Errorf("findProbableParent() = pid %d alive %v evidence %q, want pid %d alive %v evidence %q",
+					got.PID, got.Alive, got.Evidence, tt.wantPID, tt.wantAlive, tt.wantEvidence)
+			}
+		})
+	}
+}
+
+func TestReadLineage(t *testing.T) {
+	const hup = 1 // SIGHUP in the SigIgn mask
+	initProc := lineageProc{pid: 1, sid: 1, pgid: 1, start: 1, comm: "init", cgroup: "/init.scope"}
+	shell := lineageProc{pid: 100, ppid: 90, pgid: 100, sid: 100, tty: 34816, tpgid: 100, start: 9000, comm: "bash"}
+	nohupDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(nohupDir, "nohup.out"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		procs        []lineageProc
+		parent       model.Process
+		target       model.Process
+		want         *model.Lineage // Evidence and ProbableParent left out
+		wantEvidence []string       // among the evidence
+		wantProbable int            // PID of the probable parent, 0 for none
+	}{
+		{
+			name: "nohup from a shell that has exited",
+			procs: []lineageProc{initProc,
+				{pid: 500, ppid: 1, pgid: 500, sid: 100, start: 10000, comm: "worker", sigIgn: hup, cwd: nohupDir}},
+			parent: model.Process{PID: 1, Command: "init"},
+			target: model.Process{PID: 500, Command: "worker", Env: []string{"_=/usr/bin/nohup"}, WorkingDir: nohupDir},
+			want:   &model.Lineage{Reparented: true, Adopter: "init (pid 1)", FromShell: true, Methods: []string{"nohup"}},
+			wantEvidence: []string{
+				"environment set by a shell (_=/usr/bin/nohup)",
+				"parent exited, adopted by init (pid 1)",
+				"session leader 100 has exited",
+				"SIGHUP ignored",
+				filepath.Join(nohupDir, "nohup.out") + " exists",
+			},
+			wantProbable: 100,
+		},
+		{
+			name: "setsid from a shell",
+			procs: []lineageProc{initProc, shell,
+				{pid: 500, ppid: 1, pgid: 500, sid: 500, start: 10000, comm: "worker"}},
+			parent:       model.Process{PID: 1, Command: "init"},
+			target:       model.Process{PID: 500, Command: "worker", Env: []string{"_=/usr/bin/setsid"}},
+			want:         &model.Lineage{Reparented: true, Adopter: "init (pid 1)", FromShell: true, Methods: []string{"setsid"}},
+			wantEvidence: []string{"leads its own session"},
+		},
+		{
+			name: "double-fork daemon",
+			procs: []lineageProc{initProc,
+				{pid: 500, ppid: 1, pgid: 300, sid: 300, start: 10000, comm: "daemon"}},
+			parent:       model.Process{PID: 1, Command: "init"},
+			target:       model.Process{PID: 500, Command: "daemon"},
+			want:         &model.Lineage{Reparented: true, Adopter: "init (pid 1)", Methods: []string{"double fork"}},
+			wantEvidence: []string{"parent exited, adopted by init (pid 1)", "session leader 300 has exited"},
+			wantProbable: 300,
+		},
+		{
+			name: "adopted by a subreaper",
+			procs: []lineageProc{initProc, shell,
+				{pid: 50, ppid: 1, pgid: 50, sid: 50, start: 100, comm: "systemd"},
+				{pid: 500, ppid: 50, pgid: 500, sid: 100, tty: 34816, tpgid: 100, start: 10000, comm: "worker"}},
+			parent:       model.Process{PID: 50, Command: "systemd"},
+			target:       model.Process{PID: 500, Command: "worker"},
+			want:         &model.Lineage{Reparented: true, Adopter: "systemd (pid 50)", FromShell: true},
+			wantEvidence: []string{"session leader is bash (pid 100)", "parent exited, adopted by systemd (pid 50)"},
+			wantProbable: 100,
+		},
+		{
+			name: "background job of a running shell",
+			procs: []lineageProc{initProc, shell,
+				{pid: 500, ppid: 100, pgid: 500, sid: 100, tty: 34816, tpgid: 100, start: 10000, comm: "sleep"}},
+			parent:       shell.process(),
+			target:       model.Process{PID: 500, Command: "sleep"},
+			want:         &model.Lineage{FromShell: true, Methods: []string{"background job"}},
+			wantEvidence: []string{"not in the terminal's foreground process group"},
+		},
+		{
+			name: "foreground command of a shell",
+			procs: []lineageProc{initProc, shell,
+				{pid: 500, ppid: 100, pgid: 500, sid: 100, tty: 34816, tpgid: 500, start: 10000, comm: "vim"}},
+			parent: shell.process(),
+			target: model.Process{PID: 500, Command: "vim"},
+		},
+		{
+			name: "service started by init",
+			procs: []lineageProc{initProc,
+				{pid: 500, ppid: 1, pgid: 500, sid: 500, start: 10000, comm: "nginx", cgroup: "/system.slice/nginx.service"}},
+			parent: model.Process{PID: 1, Command: "init"},
+			target: model.Process{PID: 500, Command: "nginx"},
+		},
+		{
+			// nohup.out is looked for in the process's own working
+			// directory, not at the same path on the host
+			name: "nohup.out at the host path only",
+			procs: []lineageProc{initProc,
+				{pid: 500, ppid: 1, pgid: 500, sid: 500, start: 10000, comm: "nginx", cgroup: "/system.slice/nginx.service", sigIgn: hup}},
+			parent: model.Process{PID: 1, Command: "init"},
+			target: model.Process{PID: 500, Command: "nginx", WorkingDir: nohupDir},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeProc(t, tt.procs...)
+			got := ReadLineage([]model.Process{tt.parent, tt.target})
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("ReadLineage() = %+v, want not detached", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("ReadLineage() = nil")
+			}
+			if got.Reparented != tt.want.Reparented || got.Adopter != tt.want.Adopter ||
+				got.FromShell != tt.want.FromShell || !slices.Equal(got.Methods, tt.want.Methods) {
+				t.Errorf("ReadLineage() = %+v, want %+v", got, tt.want)
+			}
+			for _, e := range tt.wantEvidence {
+				if !slices.Contains(got.Evidence, e) {
+					t.Errorf("evidence %q missing from %q", e, got.Evidence)
+				}
+			}
+			switch {
+			case tt.wantProbable == 0 && got.ProbableParent != nil:
+				t.Errorf("ProbableParent = %+v, want none", got.ProbableParent)
+			case tt.wantProbable != 0 && (got.ProbableParent == nil || got.ProbableParent.PID != tt.wantProbable):
+				t.Errorf("ProbableParent = %+v, want pid %d", got.ProbableParent, tt.wantProbable)
+			}
+		})
+	}
+}
+
+func (p lineageProc) process() model.Process {
+	return model.Process{PID: p.pid, PPID: p.ppid, Command: p.comm}
+}
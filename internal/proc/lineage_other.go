@@ -0,0 +1,10 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ReadLineage needs job-control state from /proc and is Linux only
+func ReadLineage(ancestry []model.Process) *model.Lineage {
+	return nil
+}
@@ -0,0 +1,39 @@
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// annotateNamespaces flags, for every process in the chain, which namespaces
+// differ from PID 1 and from its parent in the chain
+func annotateNamespaces(chain []model.Process) {
+	var initNS []model.Namespace
+	if len(chain) > 0 && chain[0].PID == 1 {
+		initNS = chain[0].Namespaces
+	} else {
+		initNS = readNamespaces(1)
+	}
+
+	for i := range chain {
+		var parentNS []model.Namespace
+		if i > 0 {
+			parentNS = chain[i-1].Namespaces
+		}
+		for j := range chain[i].Namespaces {
+			ns := &chain[i].Namespaces[j]
+			if inode, ok := namespaceInode(initNS, ns.Type); ok {
+				ns.DiffersFromInit = inode != ns.Inode
+			}
+			if inode, ok := namespaceInode(parentNS, ns.Type); ok {
+				ns.DiffersFromParent = inode != ns.Inode
+			}
+		}
+	}
+}
+
+func namespaceInode(namespaces []model.Namespace, nsType string) (uint64, bool) {
+	for _, ns := range namespaces {
+		if ns.Type == nsType {
+			return ns.Inode, true
+		}
+	}
+	return 0, false
+}
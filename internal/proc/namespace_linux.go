@@ -0,0 +1,69 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var namespaceTypes = []string{"mnt", "pid", "net", "uts", "ipc", "user", "cgroup"}
+
+// parseNamespaceLink parses a /proc/<pid>/ns/* link target such as "net:[4026531992]"
+func parseNamespaceLink(link string) (uint64, bool) {
+	_, rest, ok := strings.Cut(link, ":[")
+	if !ok {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(strings.TrimSuffix(rest, "]"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return inode, true
+}
+
+// readNamespaces returns the namespaces of a process. Reading another user's
+// namespaces requires privileges, unreadable entries are skipped.
+func readNamespaces(pid int) []model.Namespace {
+	var namespaces []model.Namespace
+	for _, nsType := range namespaceTypes {
+		link, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/%s", pid, nsType))
+		if err != nil {
+			continue
+		}
+		if inode, ok := parseNamespaceLink(link); ok {
+			namespaces = append(namespaces, model.Namespace{Type: nsType, Inode: inode})
+		}
+	}
+	return namespaces
+}
+
+// NetNamespace returns the network namespace inode of a process
+func NetNamespace(pid int) (uint64, bool) {
+	link, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		return 0, false
+	}
+	return parseNamespaceLink(link)
+}
+
+// NetNamespaceLabel describes the network namespace of a process when it is not
+// the one witr runs in, e.g. "netns 4026532201, container 3f2a9c1b7d4e"
+func NetNamespaceLabel(pid int) string {
+	inode, ok := NetNamespace(pid)
+	if !ok {
+		return ""
+	}
+	if self, ok := NetNamespace(os.Getpid()); ok && self == inode {
+		return ""
+	}
+	label := "netns " + strconv.FormatUint(inode, 10)
+	if id := ContainerID(pid); id != "" {
+		label += ", container " + id[:12]
+	}
+	return label
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+func readNamespaces(pid int) []model.Namespace {
+	return nil
+}
+
+// NetNamespaceLabel describes the network namespace of a process (Linux only)
+func NetNamespaceLabel(pid int) string {
+	return ""
+}
@@ -0,0 +1,41 @@
+package proc
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestAnnotateNamespaces(t *testing.T) {
+	chain := []model.Process{
+		{PID: 1, Namespaces: []model.Namespace{{Type: "net", Inode: 100}, {Type: "pid", Inode: 200}}},
+		{PID: 50, Namespaces: []model.Namespace{{Type: "net", Inode: 100}, {Type: "pid", Inode: 200}}},
+		{PID: 60, Namespaces: []model.Namespace{{Type: "net", Inode: 101}, {Type: "pid", Inode: 201}}},
+		{PID: 70, Namespaces: []model.Namespace{{Type: "net", Inode: 101}, {Type: "pid", Inode: 202}}},
+	}
+
+	annotateNamespaces(chain)
+
+	tests := []struct {
+		idx        int
+		nsType     string
+		wantInit   bool
+		wantParent bool
+	}{
+		{idx: 1, nsType: "net", wantInit: false, wantParent: false},
+		{idx: 2, nsType: "net", wantInit: true, wantParent: true},
+		{idx: 3, nsType: "net", wantInit: true, wantParent: false},
+		{idx: 3, nsType: "pid", wantInit: true, wantParent: true},
+	}
+	for _, tt := range tests {
+		for _, ns := range chain[tt.idx].Namespaces {
+			if ns.Type != tt.nsType {
+				continue
+			}
+			if ns.DiffersFromInit != tt.wantInit || ns.DiffersFromParent != tt.wantParent {
+				t.Errorf("pid %d %s: DiffersFromInit=%t DiffersFromParent=%t, want %t %t",
+					chain[tt.idx].PID, ns.Type, ns.DiffersFromInit, ns.DiffersFromParent, tt.wantInit, tt.wantParent)
+			}
+		}
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"strings"
 )
 
-func readListeningSockets() (map[string]Socket, error) {
+// readListeningSockets reads the TCP listeners in a /proc/<pid>/net
+// directory, i.e. those of the process's network namespace
+func readListeningSockets(netDir string) map[string]Socket {
 	sockets := make(map[string]Socket)
 
 	parse := func(path string, ipv6 bool) {
@@ -48,10 +50,10 @@ func readListeningSockets() (map[string]Socket, error) {
 		}
 	}
 
-	parse("/proc/net/tcp", false)
-	parse("/proc/net/tcp6", true)
+	parse(netDir+"/tcp", false)
+	parse(netDir+"/tcp6", true)
 
-	return sockets, nil
+	return sockets
 }
 
 func parseAddr(raw string, ipv6 bool) (string, int) {
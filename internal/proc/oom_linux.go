@@ -0,0 +1,280 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// kernelLogFiles are the syslog files kernel messages usually end up in
+var kernelLogFiles = []string{"/var/log/kern.log", "/var/log/messages"}
+
+var (
+	// "Out of memory: Killed process 1234 (java) total-vm:..., anon-rss:491520kB, ..."
+	// "Memory cgroup out of memory: Killed process 1234 (java) ..."
+	// The command may contain parentheses itself, nothing after it does
+	oomKilledPattern = regexp.MustCompile(`Killed process (\d+) \((.*)\)`)
+	anonRSSPattern   = regexp.MustCompile(`anon-rss:(\d+)kB`)
+	// "oom-kill:constraint=CONSTRAINT_MEMCG,...,oom_memcg=/x,task_memcg=/x,task=java,pid=1234,uid=0"
+	oomKillPattern = regexp.MustCompile(`oom-kill:(\S+)`)
+)
+
+// kernelLine is a kernel log message with its time, zero if unknown
+type kernelLine struct {
+	time time.Time
+	msg  string
+}
+
+// oomDedupSlack is how far apart the times of one kill may be in the ring
+// buffer and in syslog, which stamps the line when it receives it
+const oomDedupSlack = 10 * time.Second
+
+// ReadOOMKills returns the OOM kills recorded in the kernel ring buffer
+// (/dev/kmsg, which needs root where dmesg is restricted) and in the
+// syslog kernel log files, oldest first
+func ReadOOMKills() []model.OOMKill {
+	var sources [][]model.OOMKill
+	if lines, err := readKmsg(); err == nil {
+		sources = append(sources, parseOOMKills(lines, "/dev/kmsg"))
+	}
+	for _, file := range kernelLogFiles {
+		if lines, err := readKernelLogFile(file); err == nil {
+			sources = append(sources, parseOOMKills(lines, file))
+		}
+	}
+	return mergeOOMKills(sources...)
+}
+
+// mergeOOMKills joins the kills read from several logs, oldest first. A
+// kill in more than one of them is kept as found in the first.
+func mergeOOMKills(sources ...[]model.OOMKill) []model.OOMKill {
+	var kills []model.OOMKill
+	seen := make(map[string][]time.Time)
+	for _, found := range sources {
+		for _, k := range found {
+			key := strconv.Itoa(k.PID) + " " + k.Command
+			if !slices.ContainsFunc(seen[key], func(t time.Time) bool { return sameKillTime(t, k.Time) }) {
+				seen[key] = append(seen[key], k.Time)
+				kills = append(kills, k)
+			}
+		}
+	}
+	sort.SliceStable(kills, func(i, j int) bool { return kills[i].Time.Before(kills[j].Time) })
+	return kills
+}
+
+// sameKillTime reports whether two log lines of a kill of the same PID and
+// command can be the same kill. A PID reused by the same command is killed
+// again later; a line without a time cannot be told apart.
+func sameKillTime(a, b time.Time) bool {
+	if a.IsZero() || b.IsZero() {
+		return true
+	}
+	d := a.Sub(b)
+	return d < oomDedupSlack && d > -oomDedupSlack
+}
+
+// ReadCgroupMemory returns the memory limit, usage and OOM record of a
+// process's cgroup. Kills of its siblings or of earlier incarnations of a
+// service stay in the kernel log after the cgroup counters are reset by a
+// restart, but reading the whole log is slow, so it is only searched when
+// the counters show kills or with logs set.
+func ReadCgroupMemory(pid int, command string, logs bool) *model.CgroupMemory {
+	dirs, ok := readCgroupDirs(pid)
+	if !ok {
+		return nil
+	}
+	mem := &model.CgroupMemory{Path: dirs.path}
+	mem.MemoryMax, mem.MemoryCurrent = readCgroupMemory(dirs)
+	if dirs.memoryV1() {
+		// "oom_kill" appeared in memory.oom_control with 4.13
+		mem.OOMKills = readCgroupKeyed(dirs.memory, "memory.oom_control")["oom_kill"]
+	} else {
+		events := readCgroupKeyed(dirs.memory, "memory.events")
+		mem.OOMEvents, mem.OOMKills = events["oom"], events["oom_kill"]
+	}
+	if mem.OOMKills > 0 || logs {
+		mem.Kills, mem.SameCommandKills = cgroupOOMKills(ReadOOMKills(), dirs.path, command)
+	}
+	return mem
+}
+
+// cgroupOOMKills picks the kills of processes in cgroup (or below it) and,
+// apart, those of processes running command whose log lines name no cgroup,
+// which may have been anywhere on the host
+func cgroupOOMKills(kills []model.OOMKill, cgroup, command string) (inCgroup, sameCommand []model.OOMKill) {
+	for _, k := range kills {
+		switch {
+		case k.TaskCgroup == "":
+			if k.Command == command {
+				sameCommand = append(sameCommand, k)
+			}
+		case cgroup != "" && cgroup != "/":
+			if k.TaskCgroup == cgroup || strings.HasPrefix(k.TaskCgroup, cgroup+"/") {
+				inCgroup = append(inCgroup, k)
+			}
+		}
+	}
+	return inCgroup, sameCommand
+}
+
+// parseOOMKills pairs the "oom-kill:" summary (cgroups) with the
+// "Killed process" line (memory use) the kernel logs for each kill
+func parseOOMKills(lines []kernelLine, source string) []model.OOMKill {
+	var kills []model.OOMKill
+	pending := make(map[int]int) // pid -> index of a kill still missing one of its lines
+	for _, line := range lines {
+		var k model.OOMKill
+		summary := false
+		if m := oomKillPattern.FindStringSubmatch(line.msg); m != nil {
+			summary = true
+			for _, kv := range strings.Split(m[1], ",") {
+				key, value, _ := strings.Cut(kv, "=")
+				switch key {
+				case "oom_memcg":
+					k.MemCgroup = value
+				case "task_memcg":
+					k.TaskCgroup = value
+				case "task":
+					k.Command = value
+				case "pid":
+					k.PID, _ = strconv.Atoi(value)
+				}
+			}
+		} else if m := oomKilledPattern.FindStringSubmatch(line.msg); m != nil {
+			k.PID, _ = strconv.Atoi(m[1])
+			k.Command = m[2]
+			if rss := anonRSSPattern.FindStringSubmatch(line.msg); rss != nil {
+				k.AnonRSSKB, _ = strconv.ParseInt(rss[1], 10, 64)
+			}
+		} else {
+			continue
+		}
+		if k.PID == 0 {
+			continue
+		}
+
+		if i, ok := pending[k.PID]; ok {
+			delete(pending, k.PID)
+			if summary {
+				kills[i].MemCgroup, kills[i].TaskCgroup = k.MemCgroup, k.TaskCgroup
+			} else {
+				kills[i].AnonRSSKB = k.AnonRSSKB
+			}
+			continue
+		}
+		k.Time = line.time
+		k.Source = source
+		kills = append(kills, k)
+		pending[k.PID] = len(kills) - 1
+	}
+	return kills
+}
+
+// readKmsg reads the kernel ring buffer. Records are
+// "<prio>,<seq>,<usec since boot>,<flags>;<message>" followed by
+// continuation lines.
+func readKmsg() ([]kernelLine, error) {
+	// Raw syscalls: through os.File the read would wait for new messages
+	// instead of returning EAGAIN at the end of the buffer
+	fd, err := syscall.Open("/dev/kmsg", syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	boot := bootTime()
+	var lines []kernelLine
+	buf := make([]byte, 8192)
+	for {
+		n, err := syscall.Read(fd, buf)
+		if errors.Is(err, syscall.EPIPE) {
+			// Records were overwritten while reading, carry on with the next
+			continue
+		}
+		if err != nil || n <= 0 {
+			break
+		}
+		header, msg, ok := strings.Cut(string(buf[:n]), ";")
+		if !ok {
+			continue
+		}
+		msg, _, _ = strings.Cut(msg, "\n")
+		if !strings.Contains(msg, "Killed process") && !strings.Contains(msg, "oom-kill:") {
+			continue
+		}
+		line := kernelLine{msg: msg}
+		if fields := strings.Split(header, ","); len(fields) > 2 {
+			if usec, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+				line.time = boot.Add(time.Duration(usec) * time.Microsecond)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// readKernelLogFile reads the OOM related kernel messages of a syslog file
+func readKernelLogFile(file string) ([]kernelLine, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []kernelLine
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if !strings.Contains(text, "Killed process") && !strings.Contains(text, "oom-kill:") {
+			continue
+		}
+		lines = append(lines, parseSyslogLine(text, time.Now()))
+	}
+	return lines, scanner.Err()
+}
+
+// parseSyslogLine splits "Oct 19 05:56:52 host kernel: [123.4] message" or
+// "2026-10-19T05:56:52.123+00:00 host kernel: message" into time and
+// message. Traditional syslog stamps have no year: the latest one not
+// after now is assumed.
+func parseSyslogLine(text string, now time.Time) kernelLine {
+	line := kernelLine{msg: text}
+	if _, msg, ok := strings.Cut(text, "kernel: "); ok {
+		if strings.HasPrefix(msg, "[") {
+			if end := strings.Index(msg, "] "); end != -1 {
+				msg = msg[end+2:]
+			}
+		}
+		line.msg = msg
+	}
+
+	if stamp, _, ok := strings.Cut(text, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			line.time = t
+			return line
+		}
+	}
+	if len(text) >= 15 {
+		if t, err := time.ParseInLocation("Jan _2 15:04:05", text[:15], now.Location()); err == nil {
+			t = t.AddDate(now.Year(), 0, 0)
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+			line.time = t
+		}
+	}
+	return line
+}
@@ -0,0 +1,117 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseOOMKills(t *testing.T) {
+	t0 := time.Date(2026, 10, 19, 6, 1, 8, 0, time.UTC)
+	lines := []kernelLine{
+		{t0, "oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=/,mems_allowed=0,oom_memcg=/system.slice/app.service,task_memcg=/system.slice/app.service,task=java,pid=1234,uid=0"},
+		{t0, "Memory cgroup out of memory: Killed process 1234 (java) total-vm:2000000kB, anon-rss:491520kB, file-rss:0kB, shmem-rss:0kB, UID:0 pgtables:1200kB oom_score_adj:0"},
+		{t0.Add(time.Hour), "Out of memory: Killed process 999 (my (weird) app) total-vm:100kB, anon-rss:2048kB, file-rss:0kB"},
+	}
+	want := []model.OOMKill{
+		{Time: t0, PID: 1234, Command: "java", MemCgroup: "/system.slice/app.service", TaskCgroup: "/system.slice/app.service", AnonRSSKB: 491520, Source: "/dev/kmsg"},
+		{Time: t0.Add(time.Hour), PID: 999, Command: "my (weird) app", AnonRSSKB: 2048, Source: "/dev/kmsg"},
+	}
+
+	got := parseOOMKills(lines, "/dev/kmsg")
+	if len(got) != len(want) {
+		t.Fatalf("parseOOMKills() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("kill %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMergeOOMKills(t *testing.T) {
+	t0 := time.Date(2026, 10, 19, 6, 1, 8, 0, time.UTC)
+	kmsg := []model.OOMKill{
+		{Time: t0, PID: 1234, Command: "java", Source: "/dev/kmsg"},
+	}
+	syslog := []model.OOMKill{
+		// The same kill, stamped by syslog a moment later
+		{Time: t0.Add(time.Second), PID: 1234, Command: "java", Source: "/var/log/kern.log"},
+		// The PID reused by the same command and killed again
+		{Time: t0.Add(time.Hour), PID: 1234, Command: "java", Source: "/var/log/kern.log"},
+		{Time: t0.Add(-time.Hour), PID: 77, Command: "node", Source: "/var/log/kern.log"},
+	}
+
+	got := mergeOOMKills(kmsg, syslog)
+	want := []model.OOMKill{syslog[2], kmsg[0], syslog[1]}
+	if len(got) != len(want) {
+		t.Fatalf("mergeOOMKills() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("kill %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseSyslogLine(t *testing.T) {
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		line     string
+		wantTime time.Time
+		wantMsg  string
+	}{
+		{
+			line:     "Oct 19 05:56:52 web1 kernel: [12345.678901] Out of memory: Killed process 1 (x)",
+			wantTime: time.Date(2025, 10, 19, 5, 56, 52, 0, time.UTC),
+			wantMsg:  "Out of memory: Killed process 1 (x)",
+		},
+		{
+			line:     "2026-01-05T11:59:00.250000+00:00 web1 kernel: oom-kill:task=x,pid=1",
+			wantTime: time.Date(2026, 1, 5, 11, 59, 0, 250000000, time.UTC),
+			wantMsg:  "oom-kill:task=x,pid=1",
+		},
+	}
+	for _, tt := range tests {
+		got := parseSyslogLine(tt.line, now)
+		if !got.time.Equal(tt.wantTime) || got.msg != tt.wantMsg {
+			t.Errorf("parseSyslogLine(%q) = %v %q, want %v %q", tt.line, got.time, got.msg, tt.wantTime, tt.wantMsg)
+		}
+	}
+}
+
+func TestCgroupOOMKills(t *testing.T) {
+	kills := []model.OOMKill{
+		{PID: 1, Command: "java", TaskCgroup: "/system.slice/app.service"},
+		{PID: 2, Command: "worker", TaskCgroup: "/system.slice/app.service/workers"},
+		{PID: 3, Command: "java", TaskCgroup: "/system.slice/other.service"},
+		{PID: 4, Command: "java"},
+		{PID: 5, Command: "python3"},
+		{PID: 6, Command: "java", TaskCgroup: "/system.slice/app.service2"},
+	}
+	tests := []struct {
+		cgroup, command string
+		want, wantSame  []int
+	}{
+		{cgroup: "/system.slice/app.service", command: "java", want: []int{1, 2}, wantSame: []int{4}},
+		{cgroup: "/", command: "java", want: nil, wantSame: []int{4}},
+		{cgroup: "/user.slice", command: "bash", want: nil, wantSame: nil},
+	}
+	pids := func(kills []model.OOMKill) []int {
+		var out []int
+		for _, k := range kills {
+			out = append(out, k.PID)
+		}
+		return out
+	}
+	for _, tt := range tests {
+		in, same := cgroupOOMKills(kills, tt.cgroup, tt.command)
+		if fmt.Sprint(pids(in)) != fmt.Sprint(tt.want) || fmt.Sprint(pids(same)) != fmt.Sprint(tt.wantSame) {
+			t.Errorf("cgroupOOMKills(%q, %q) = %v, %v, want %v, %v", tt.cgroup, tt.command, pids(in), pids(same), tt.want, tt.wantSame)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ReadOOMKills reads the Linux kernel log; there is nothing to find elsewhere
+func ReadOOMKills() []model.OOMKill {
+	return nil
+}
+
+// ReadCgroupMemory reads Linux cgroups
+func ReadCgroupMemory(pid int, command string, logs bool) *model.CgroupMemory {
+	return nil
+}
@@ -0,0 +1,335 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const (
+	dpkgStatusFile   = "/var/lib/dpkg/status"
+	dpkgInfoDir      = "/var/lib/dpkg/info"
+	apkInstalledFile = "/lib/apk/db/installed"
+)
+
+// executableReadPath reads the executable through /proc so the bytes hashed
+// are the ones the process is running, even if the file was replaced
+func executableReadPath(pid int, exe string) string {
+	return fmt.Sprintf("/proc/%d/exe", pid)
+}
+
+// packagedFile is a file as recorded by the package owning it
+type packagedFile struct {
+	manager, name, version string
+	// Checksum recorded by the package, and how to compute the same kind
+	// of checksum of a file to compare with it
+	checksum string
+	digest   func(path string) string
+}
+
+// findPackagedFile looks path up in whichever package database the host
+// has; available reports whether there is one at all
+func findPackagedFile(path string) (pf packagedFile, found, available bool) {
+	switch {
+	case fileExists(dpkgStatusFile):
+		pf, found = findDpkg(path)
+		return pf, found, true
+	case fileExists(apkInstalledFile):
+		pf, found = findApk(path)
+		return pf, found, true
+	default:
+		if _, err := exec.LookPath("rpm"); err == nil {
+			pf, found = findRpm(path)
+			return pf, found, true
+		}
+	}
+	return packagedFile{}, false, false
+}
+
+// PackageOwner returns the package owning path as "name version", empty
+// when no package database lists it. A file deleted by an upgrade is
+// looked up by the path it had, so this names the package that replaced it.
+func PackageOwner(path string) string {
+	pf, found, _ := findPackagedFile(path)
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(pf.name + " " + pf.version)
+}
+
+// lookupPackage fills package ownership and verification using whichever
+// package database the host has
+func lookupPackage(bin *model.BinaryInfo, readPath string) {
+	pf, found, available := findPackagedFile(bin.Path)
+	bin.PackageDBAvailable = available
+	if !found {
+		return
+	}
+	bin.PackageManager = pf.manager
+	bin.Package = pf.name
+	bin.PackageVersion = pf.version
+
+	// The executable is only read when there is a checksum to compare with
+	actual := ""
+	if pf.checksum != "" {
+		actual = pf.digest(readPath)
+	}
+	switch {
+	case pf.checksum == "" || actual == "":
+		bin.Verification = model.BinaryUnverifiable
+	case strings.EqualFold(pf.checksum, actual):
+		bin.Verification = model.BinaryVerified
+	default:
+		bin.Verification = model.BinaryModified
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// pathAliases accounts for merged-/usr systems where packages list /bin/x
+// but the process runs /usr/bin/x (or the other way round)
+func pathAliases(path string) []string {
+	if rest, ok := strings.CutPrefix(path, "/usr/"); ok {
+		return []string{path, "/" + rest}
+	}
+	return []string{path, "/usr" + path}
+}
+
+func fileDigest(path string, h hash.Hash) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// dpkgIndex maps every path installed by dpkg to the package whose
+// <pkg>.list names it, read once per invocation. It is not cached on disk:
+// verification must not trust a file someone other than root could edit.
+var dpkgIndex = sync.OnceValue(func() map[string]string {
+	return loadDpkgIndex(dpkgInfoDir)
+})
+
+// loadDpkgIndex reads the list files of infoDir
+func loadDpkgIndex(infoDir string) map[string]string {
+	index := make(map[string]string)
+	lists, _ := filepath.Glob(filepath.Join(infoDir, "*.list"))
+	for _, list := range lists {
+		data, err := os.ReadFile(list)
+		if err != nil {
+			continue
+		}
+		pkg := strings.TrimSuffix(filepath.Base(list), ".list")
+		for path := range strings.Lines(string(data)) {
+			index[strings.TrimSuffix(path, "\n")] = pkg
+		}
+	}
+	return index
+}
+
+// findDpkg finds the owning package in the dpkg path index, its version
+// from the status file and the packaged md5 from <pkg>.md5sums
+func findDpkg(path string) (packagedFile, bool) {
+	index := dpkgIndex()
+	for _, listed := range pathAliases(path) {
+		pkg, ok := index[listed]
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(pkg, ":")
+		return packagedFile{
+			manager:  "dpkg",
+			name:     name,
+			version:  dpkgVersion(dpkgStatusFile, name),
+			checksum: dpkgChecksum(filepath.Join(dpkgInfoDir, pkg+".md5sums"), listed),
+			digest:   func(path string) string { return fileDigest(path, md5.New()) },
+		}, true
+	}
+	return packagedFile{}, false
+}
+
+// dpkgVersion returns the installed version of a package from the dpkg status file
+func dpkgVersion(statusFile, name string) string {
+	f, err := os.Open(statusFile)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inPackage := false
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			inPackage = false
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "Package: "); ok {
+			inPackage = v == name
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "Version: "); ok && inPackage {
+			return v
+		}
+	}
+	return ""
+}
+
+// dpkgChecksum finds the md5 of path in a <pkg>.md5sums file ("<md5>  usr/bin/x")
+func dpkgChecksum(md5sums, path string) string {
+	data, err := os.ReadFile(md5sums)
+	if err != nil {
+		return ""
+	}
+	rel := strings.TrimPrefix(path, "/")
+	for _, line := range strings.Split(string(data), "\n") {
+		sum, file, ok := strings.Cut(line, "  ")
+		if ok && file == rel {
+			return sum
+		}
+	}
+	return ""
+}
+
+// apkEntry is the package owning a file in the apk installed database
+type apkEntry struct {
+	name, version, checksum string
+}
+
+// parseApkInstalled looks up path in /lib/apk/db/installed, where each package
+// stanza has P: (name), V: (version), F: (directory), R: (file in the last
+// directory) and Z: (checksum of the last file, "Q1" + base64 sha1)
+func parseApkInstalled(content string, aliases []string) (apkEntry, bool) {
+	var cur apkEntry
+	var dir string
+	matched := false
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" {
+			if matched {
+				return cur, true
+			}
+			cur, dir = apkEntry{}, ""
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "P":
+			cur.name = value
+		case "V":
+			cur.version = value
+		case "F":
+			dir = value
+		case "R":
+			if matched {
+				return cur, true
+			}
+			matched = slices.Contains(aliases, "/"+dir+"/"+value)
+		case "Z":
+			if matched {
+				cur.checksum = value
+			}
+		}
+	}
+	return cur, matched
+}
+
+func findApk(path string) (packagedFile, bool) {
+	data, err := os.ReadFile(apkInstalledFile)
+	if err != nil {
+		return packagedFile{}, false
+	}
+	entry, ok := parseApkInstalled(string(data), pathAliases(path))
+	if !ok {
+		return packagedFile{}, false
+	}
+	return packagedFile{
+		manager:  "apk",
+		name:     entry.name,
+		version:  entry.version,
+		checksum: entry.checksum,
+		digest: func(path string) string {
+			sum := fileDigest(path, sha1.New())
+			if sum == "" {
+				return ""
+			}
+			raw, _ := hex.DecodeString(sum)
+			return "Q1" + base64.StdEncoding.EncodeToString(raw)
+		},
+	}, true
+}
+
+// rpmTimeout bounds each rpm query, which waits for as long as another
+// process holds the rpm database lock
+const rpmTimeout = 5 * time.Second
+
+// findRpm asks rpm for the owning package and its per-file digests. The
+// rpm database itself is not a plain-text format, so the rpm CLI is used.
+func findRpm(path string) (packagedFile, bool) {
+	for _, alias := range pathAliases(path) {
+		ctx, cancel := context.WithTimeout(context.Background(), rpmTimeout)
+		out, err := exec.CommandContext(ctx, "rpm", "-qf", "--qf",
+			"%{NAME}\t%{VERSION}-%{RELEASE}\t%{FILEDIGESTALGO}\n[%{FILENAMES}\t%{FILEDIGESTS}\n]", alias).Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		header := strings.Split(lines[0], "\t")
+		if len(header) < 3 {
+			continue
+		}
+
+		var packaged string
+		for _, line := range lines[1:] {
+			if file, sum, ok := strings.Cut(line, "\t"); ok && file == alias {
+				packaged = sum
+				break
+			}
+		}
+
+		newHash := md5.New // 1 and older rpm versions without the tag
+		switch header[2] {
+		case "2":
+			newHash = sha1.New
+		case "8":
+			newHash = sha256.New
+		}
+		return packagedFile{
+			manager:  "rpm",
+			name:     header[0],
+			version:  header[1],
+			checksum: packaged,
+			digest:   func(path string) string { return fileDigest(path, newHash()) },
+		}, true
+	}
+	return packagedFile{}, false
+}
@@ -0,0 +1,63 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDpkgStatusAndChecksums(t *testing.T) {
+	dir := t.TempDir()
+	status := filepath.Join(dir, "status")
+	os.WriteFile(status, []byte("Package: nginx-common\nStatus: install ok installed\nVersion: 1.24.0-1\n\n"+
+		"Package: nginx\nStatus: install ok installed\nDescription: small, powerful\n web server\nVersion: 1.24.0-2\n\n"), 0o644)
+	md5sums := filepath.Join(dir, "nginx.md5sums")
+	os.WriteFile(md5sums, []byte("d41d8cd98f00b204e9800998ecf8427e  usr/share/doc/nginx/copyright\n"+
+		"0123456789abcdef0123456789abcdef  usr/sbin/nginx\n"), 0o644)
+
+	if got := dpkgVersion(status, "nginx"); got != "1.24.0-2" {
+		t.Errorf("dpkgVersion() = %q, want 1.24.0-2", got)
+	}
+	if got := dpkgVersion(status, "missing"); got != "" {
+		t.Errorf("dpkgVersion(missing) = %q, want empty", got)
+	}
+	if got := dpkgChecksum(md5sums, "/usr/sbin/nginx"); got != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("dpkgChecksum() = %q", got)
+	}
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	db := "P:musl\nV:1.2.4-r2\nF:lib\nR:ld-musl-x86_64.so.1\nZ:Q1aaaa=\n\n" +
+		"P:nginx\nV:1.24.0-r7\nF:etc/nginx\nR:nginx.conf\nZ:Q1bbbb=\nF:usr/sbin\nR:nginx\na:0:0:755\nZ:Q1cccc=\nR:nginx-debug\nZ:Q1dddd=\n\n"
+
+	entry, ok := parseApkInstalled(db, pathAliases("/usr/sbin/nginx"))
+	if !ok || entry.name != "nginx" || entry.version != "1.24.0-r7" || entry.checksum != "Q1cccc=" {
+		t.Fatalf("parseApkInstalled() = %+v, %v", entry, ok)
+	}
+	if _, ok := parseApkInstalled(db, pathAliases("/usr/bin/curl")); ok {
+		t.Fatalf("parseApkInstalled() matched an unowned path")
+	}
+}
+
+func TestPathAliases(t *testing.T) {
+	if got := pathAliases("/usr/bin/sleep"); got[1] != "/bin/sleep" {
+		t.Errorf("pathAliases(/usr/bin/sleep) = %v", got)
+	}
+	if got := pathAliases("/sbin/init"); got[1] != "/usr/sbin/init" {
+		t.Errorf("pathAliases(/sbin/init) = %v", got)
+	}
+}
+
+func TestLoadDpkgIndex(t *testing.T) {
+	info := t.TempDir()
+	os.WriteFile(filepath.Join(info, "nginx.list"), []byte("/.\n/usr\n/usr/sbin/nginx\n"), 0o644)
+	os.WriteFile(filepath.Join(info, "libc6:amd64.list"), []byte("/usr/lib/x86_64-linux-gnu/libc.so.6\n"), 0o644)
+	os.WriteFile(filepath.Join(info, "nginx.md5sums"), []byte("0123  usr/sbin/nginx\n"), 0o644)
+
+	index := loadDpkgIndex(info)
+	if index["/usr/sbin/nginx"] != "nginx" || index["/usr/lib/x86_64-linux-gnu/libc.so.6"] != "libc6:amd64" || len(index) != 4 {
+		t.Fatalf("loadDpkgIndex() = %v", index)
+	}
+}
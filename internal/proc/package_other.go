@@ -0,0 +1,17 @@
+//go:build !linux
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+func executableReadPath(pid int, exe string) string {
+	return exe
+}
+
+// PackageOwner returns "": package ownership is only checked on Linux
+func PackageOwner(path string) string {
+	return ""
+}
+
+// lookupPackage is a no-op: package ownership is only checked on Linux
+func lookupPackage(bin *model.BinaryInfo, readPath string) {}
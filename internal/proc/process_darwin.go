@@ -68,7 +68,6 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Health status
 	health := "healthy"
-	forked := "unknown"
 
 	switch state {
 	case "Z":
@@ -77,13 +76,6 @@ func ReadProcess(pid int) (model.Process, error) {
 		health = "stopped"
 	}
 
-	// Fork detection
-	if ppid != 1 && comm != "launchd" {
-		forked = "forked"
-	} else {
-		forked = "not-forked"
-	}
-
 	// Get user from UID
 	user := readUserByUID(uid)
 
@@ -137,7 +129,7 @@ func ReadProcess(pid int) (model.Process, error) {
 		ListeningPorts: ports,
 		BindAddresses:  addrs,
 		Health:         health,
-		Forked:         forked,
+		Forked:         legacyForked(ppid, comm == "launchd"),
 		Env:            env,
 	}, nil
 }
@@ -65,7 +65,6 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Health status
 	health := "healthy"
-	forked := "unknown"
 
 	// FreeBSD states can be multi-character like "Is", "Ss", "R", "Z", "T"
 	// Check first character for main state
@@ -78,13 +77,6 @@ func ReadProcess(pid int) (model.Process, error) {
 		}
 	}
 
-	// Fork detection
-	if ppid != 1 && comm != "init" {
-		forked = "forked"
-	} else {
-		forked = "not-forked"
-	}
-
 	// Get user from UID
 	user := readUserByUID(uid)
 
@@ -133,7 +125,7 @@ func ReadProcess(pid int) (model.Process, error) {
 		ListeningPorts: ports,
 		BindAddresses:  addrs,
 		Health:         health,
-		Forked:         forked,
+		Forked:         legacyForked(ppid, comm == "init"),
 		Env:            env,
 	}, nil
 }
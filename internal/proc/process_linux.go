@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -46,15 +47,21 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Read all proc files in a logical order to minimize TOCTOU issues
 	// Start with stat file which is most likely to fail if process disappears
-	statPath := fmt.Sprintf("/proc/%d/stat", pid)
-	stat, err := os.ReadFile(statPath)
+	table := Snapshot()
+	stat, err := table.Stat(pid)
 	if err != nil {
 		return model.Process{}, fmt.Errorf("process %d disappeared during read", pid)
 	}
 
+	// Past the --timeout deadline, skip what walks the filesystem or forks
+	expired := table.Expired()
+	if expired {
+		table.MarkIncomplete(pid)
+	}
+
 	// Read environment variables
 	env := []string{}
-	envBytes, errEnv := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
+	envBytes, errEnv := table.file(pid, "environ")
 	if errEnv == nil {
 		for _, e := range strings.Split(string(envBytes), "\x00") {
 			if e != "" {
@@ -64,10 +71,9 @@ func ReadProcess(pid int) (model.Process, error) {
 	}
 	// Health status
 	health := "healthy"
-	forked := "unknown"
 
 	// Working directory
-	var cwd, cwdErr = os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+	var cwd, cwdErr = table.Readlink(pid, "cwd")
 	if cwdErr != nil {
 		cwd = "unknown"
 	} else {
@@ -79,8 +85,7 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	// Container detection
 	container := ""
-	cgroupFile := fmt.Sprintf("/proc/%d/cgroup", pid)
-	if cgroupData, err := os.ReadFile(cgroupFile); err == nil {
+	if cgroupData, err := table.Cgroup(pid); err == nil {
 		cgroupStr := string(cgroupData)
 		switch {
 		case strings.Contains(cgroupStr, "docker"):
@@ -96,54 +101,25 @@ func ReadProcess(pid int) (model.Process, error) {
 		}
 	}
 
-	// Service detection (try systemctl show for this PID)
+	// Service detection (systemctl status, shared by processes in the same cgroup)
 	service := ""
-	svcOut, err := exec.Command("systemctl", "status", fmt.Sprintf("%d", pid)).CombinedOutput()
-	if err == nil && strings.Contains(string(svcOut), "Loaded: loaded") {
-		// Try to extract service name from output
-		for line := range strings.Lines(string(svcOut)) {
-			if strings.HasPrefix(line, "Loaded:") && strings.Contains(line, ".service") {
-				parts := strings.Fields(line)
-				for _, part := range parts {
-					if strings.HasSuffix(part, ".service") {
-						service = part
-						break
-					}
-				}
-			}
-		}
+	if !expired {
+		service = table.Service(pid)
 	}
 
-	// Git repo/branch detection (walk up to find .git)
+	// Git repo/branch detection, bounded like a /proc read since the
+	// working directory may be on a hung network mount
 	gitRepo := ""
 	gitBranch := ""
-	if cwd != "unknown" {
-		searchDir := cwd
-		for searchDir != "/" && searchDir != "." && searchDir != "" {
-			gitDir := searchDir + "/.git"
-			if fi, err := os.Stat(gitDir); err == nil && fi.IsDir() {
-				// Repo name is the base dir
-				parts := strings.Split(strings.TrimRight(searchDir, "/"), "/")
-				gitRepo = parts[len(parts)-1]
-				// Try to read HEAD for branch
-				headFile := gitDir + "/HEAD"
-				if head, err := os.ReadFile(headFile); err == nil {
-					headStr := strings.TrimSpace(string(head))
-					if strings.HasPrefix(headStr, "ref: ") {
-						ref := strings.TrimPrefix(headStr, "ref: ")
-						refParts := strings.Split(ref, "/")
-						gitBranch = refParts[len(refParts)-1]
-					}
-				}
-				break
-			}
-			// Move up one directory
-			idx := strings.LastIndex(searchDir, "/")
-			if idx <= 0 {
-				break
-			}
-			searchDir = searchDir[:idx]
+	if cwd != "unknown" && cwd != "invalid" && !expired {
+		repo, err := timed(func() ([2]string, error) {
+			name, branch := findGitRepo(cwd)
+			return [2]string{name, branch}, nil
+		})
+		if err != nil {
+			table.MarkIncomplete(pid)
 		}
+		gitRepo, gitBranch = repo[0], repo[1]
 	}
 
 	// stat format is evil, command is inside ()
@@ -161,13 +137,6 @@ func ReadProcess(pid int) (model.Process, error) {
 	state := processState(fields)
 	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
 
-	// Fork detection: if ppid != 1 and not systemd, likely forked; also check for vfork/fork/clone flags if possible
-	if ppid != 1 && comm != "systemd" {
-		forked = "forked"
-	} else {
-		forked = "not-forked"
-	}
-
 	startedAt := bootTime().Add(time.Duration(startTicks) * time.Second / ticksPerSecond())
 
 	// Health: zombie/stopped
@@ -178,26 +147,23 @@ func ReadProcess(pid int) (model.Process, error) {
 		health = "stopped"
 	}
 
-	// High CPU/memory (simple: >80% of total)
-	utime, _ := strconv.ParseFloat(fields[11], 64)
-	stime, _ := strconv.ParseFloat(fields[12], 64)
-	rssPages, _ := strconv.ParseFloat(fields[21], 64)
-	clkTck := float64(ticksPerSecond())
-	totalCPU := (utime + stime) / clkTck
-	if totalCPU > 60*60*2 { // >2h CPU time
+	// High CPU/memory, relative to the process's age and its cgroup limits
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+	memoryMax, cpuQuota := cgroupLimits(pid)
+	cpuTime := time.Duration(utime+stime) * time.Second / ticksPerSecond()
+	if highCPU(cpuTime, time.Since(startedAt), cpuQuota) {
 		health = "high-cpu"
 	}
-	pageSize := float64(os.Getpagesize())
-	memBytes := rssPages * pageSize
-	memMB := memBytes / (1024 * 1024)
-	if memMB > 1024 {
+	if highMemory(rssPages*uint64(os.Getpagesize()), memoryMax) {
 		health = "high-mem"
 	}
 
 	user := readUser(pid)
 
-	sockets, _ := readListeningSockets()
-	inodes := socketsForPID(pid)
+	sockets := table.Listeners(pid)
+	inodes := table.SocketInodes(pid)
 
 	var ports []int
 	var addrs []string
@@ -209,11 +175,21 @@ func ReadProcess(pid int) (model.Process, error) {
 		}
 	}
 	// Full command line
-	cmdline := ""
-	cmdlineBytes, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
-	if err == nil {
-		cmd := strings.ReplaceAll(string(cmdlineBytes), "\x00", " ")
-		cmdline = strings.TrimSpace(cmd)
+	cmdline := table.Cmdline(pid)
+
+	exe, exeDeleted := readExe(pid)
+	var deletedLibs []string
+	if !expired {
+		deletedLibs = readDeletedLibs(pid)
+	}
+	deletedPaths := deletedLibs
+	if exeDeleted {
+		deletedLibs = slices.DeleteFunc(deletedLibs, func(l string) bool { return l == exe })
+		deletedPaths = append([]string{exe}, deletedLibs...)
+	}
+	var owners map[string]string
+	if !expired {
+		owners = deletedOwners(deletedPaths, nil)
 	}
 
 	if comm == "docker-proxy" && container == "" {
@@ -225,6 +201,7 @@ func ReadProcess(pid int) (model.Process, error) {
 		PPID:           ppid,
 		Command:        comm,
 		Cmdline:        cmdline,
+		Exe:            exe,
 		StartedAt:      startedAt,
 		User:           user,
 		WorkingDir:     cwd,
@@ -235,11 +212,51 @@ func ReadProcess(pid int) (model.Process, error) {
 		ListeningPorts: ports,
 		BindAddresses:  addrs,
 		Health:         health,
-		Forked:         forked,
+		Forked:         legacyForked(ppid, comm == "systemd"),
 		Env:            env,
+		Security:       readSecurityContext(pid),
+		Namespaces:     readNamespaces(pid),
+		Login:          readLogin(pid),
+		ExeDeleted:     exeDeleted,
+		DeletedLibs:    deletedLibs,
+		DeletedOwners:  owners,
 	}, nil
 }
 
+// findGitRepo walks up from dir to the enclosing git repository and returns
+// its name and checked out branch
+func findGitRepo(dir string) (string, string) {
+	gitRepo := ""
+	gitBranch := ""
+	searchDir := dir
+	for searchDir != "/" && searchDir != "." && searchDir != "" {
+		gitDir := searchDir + "/.git"
+		if fi, err := os.Stat(gitDir); err == nil && fi.IsDir() {
+			// Repo name is the base dir
+			parts := strings.Split(strings.TrimRight(searchDir, "/"), "/")
+			gitRepo = parts[len(parts)-1]
+			// Try to read HEAD for branch
+			headFile := gitDir + "/HEAD"
+			if head, err := os.ReadFile(headFile); err == nil {
+				headStr := strings.TrimSpace(string(head))
+				if strings.HasPrefix(headStr, "ref: ") {
+					ref := strings.TrimPrefix(headStr, "ref: ")
+					refParts := strings.Split(ref, "/")
+					gitBranch = refParts[len(refParts)-1]
+				}
+			}
+			break
+		}
+		// Move up one directory
+		idx := strings.LastIndex(searchDir, "/")
+		if idx <= 0 {
+			break
+		}
+		searchDir = searchDir[:idx]
+	}
+	return gitRepo, gitBranch
+}
+
 func resolveDockerProxyContainer(cmdline string) string {
 	var containerIP string
 	parts := strings.Fields(cmdline)
@@ -6,12 +6,13 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 // GetResourceContext returns resource usage context for a process
-func GetResourceContext(pid int) *model.ResourceContext {
+func GetResourceContext(pid int, _ time.Duration) *model.ResourceContext {
 	ctx := &model.ResourceContext{}
 
 	// Check if process is preventing sleep
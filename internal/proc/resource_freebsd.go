@@ -2,11 +2,15 @@
 
 package proc
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 // GetResourceContext returns resource usage context for a process
 // FreeBSD implementation - basic support
-func GetResourceContext(pid int) *model.ResourceContext {
+func GetResourceContext(pid int, _ time.Duration) *model.ResourceContext {
 	// FreeBSD doesn't have macOS-style power assertions or thermal monitoring
 	// Could potentially check CPU temperature via sysctl dev.cpu.*.temperature
 	// but this is not process-specific
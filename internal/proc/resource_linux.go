@@ -2,14 +2,75 @@
 
 package proc
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 // GetResourceContext returns resource usage context for a process
-// Linux implementation - TODO: implement using /proc and cgroup info
-func GetResourceContext(pid int) *model.ResourceContext {
-	// Linux implementation could check:
-	// - /proc/<pid>/oom_score for memory pressure
-	// - cgroup CPU throttling
-	// - thermal zone info from /sys/class/thermal
-	return nil
+// Linux implementation using /proc/<pid>/stat, oom_score and the process's
+// cgroup; CPU usage is measured over cpuInterval
+func GetResourceContext(pid int, cpuInterval time.Duration) *model.ResourceContext {
+	ctx := &model.ResourceContext{}
+
+	ctx.CPUUsage = sampleCPUUsage(pid, cpuInterval)
+	ctx.OOMScore = readProcInt(pid, "oom_score")
+	ctx.OOMScoreAdj = readProcInt(pid, "oom_score_adj")
+	ctx.Cgroup = readCgroupResources(pid)
+
+	return ctx
+}
+
+// readCPUTicks returns utime+stime of a process in clock ticks
+func readCPUTicks(pid int) (float64, bool) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, false
+	}
+	raw := string(stat)
+	close := strings.LastIndex(raw, ")")
+	if close == -1 || close+2 > len(raw) {
+		return 0, false
+	}
+	fields := strings.Fields(raw[close+2:])
+	if len(fields) < 13 {
+		return 0, false
+	}
+	utime, _ := strconv.ParseFloat(fields[11], 64)
+	stime, _ := strconv.ParseFloat(fields[12], 64)
+	return utime + stime, true
+}
+
+// sampleCPUUsage measures the CPU usage of a process over interval, as a
+// percentage of one CPU (a busy multi-threaded process can exceed 100)
+func sampleCPUUsage(pid int, interval time.Duration) float64 {
+	before, ok := readCPUTicks(pid)
+	if !ok {
+		return 0
+	}
+	start := time.Now()
+	time.Sleep(interval)
+	after, ok := readCPUTicks(pid)
+	if !ok {
+		return 0
+	}
+	elapsed := time.Since(start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return (after - before) / float64(ticksPerSecond()) / elapsed * 100
+}
+
+func readProcInt(pid int, name string) int {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/%s", pid, name))
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return v
 }
@@ -7,11 +7,12 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
-func GetResourceContext(pid int) *model.ResourceContext {
+func GetResourceContext(pid int, _ time.Duration) *model.ResourceContext {
 	// wmic path Win32_PerfFormattedData_PerfProc_Process where IDProcess=PID get PercentProcessorTime,WorkingSetPrivate /format:list
 	cmd := exec.Command("wmic", "path", "Win32_PerfFormattedData_PerfProc_Process", "where", fmt.Sprintf("IDProcess=%d", pid), "get", "PercentProcessorTime,WorkingSetPrivate", "/format:list")
 	out, err := cmd.Output()
@@ -0,0 +1,146 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// capabilityNames maps capability bit numbers (include/uapi/linux/capability.h) to names
+var capabilityNames = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN",
+	"CAP_NET_RAW",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_MKNOD",
+	"CAP_LEASE",
+	"CAP_AUDIT_WRITE",
+	"CAP_AUDIT_CONTROL",
+	"CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MAC_ADMIN",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_READ",
+	"CAP_PERFMON",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// decodeCapabilities turns a hex capability mask from /proc/<pid>/status into names
+func decodeCapabilities(hexMask string) []string {
+	mask, err := strconv.ParseUint(strings.TrimSpace(hexMask), 16, 64)
+	if err != nil {
+		return nil
+	}
+	caps := []string{}
+	for bit := 0; bit < 64; bit++ {
+		if mask&(1<<uint(bit)) == 0 {
+			continue
+		}
+		if bit < len(capabilityNames) {
+			caps = append(caps, capabilityNames[bit])
+		} else {
+			caps = append(caps, "CAP_"+strconv.Itoa(bit))
+		}
+	}
+	return caps
+}
+
+// parseSecurityStatus extracts uid, capabilities, seccomp and no_new_privs from /proc/<pid>/status
+func parseSecurityStatus(status string) *model.SecurityContext {
+	sec := &model.SecurityContext{UID: -1}
+	scanner := bufio.NewScanner(strings.NewReader(status))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Uid":
+			// real, effective, saved, filesystem
+			fields := strings.Fields(value)
+			if len(fields) >= 2 {
+				if uid, err := strconv.Atoi(fields[1]); err == nil {
+					sec.UID = uid
+				}
+			}
+		case "CapEff":
+			sec.CapEff = decodeCapabilities(value)
+		case "CapPrm":
+			sec.CapPrm = decodeCapabilities(value)
+		case "CapBnd":
+			sec.CapBnd = decodeCapabilities(value)
+		case "NoNewPrivs":
+			sec.NoNewPrivs = value == "1"
+		case "Seccomp":
+			switch value {
+			case "0":
+				sec.Seccomp = "disabled"
+			case "1":
+				sec.Seccomp = "strict"
+			case "2":
+				sec.Seccomp = "filter"
+			}
+		}
+	}
+	return sec
+}
+
+// readLSMLabel returns the AppArmor profile or SELinux context of a process
+func readLSMLabel(pid int) string {
+	for _, path := range []string{
+		fmt.Sprintf("/proc/%d/attr/current", pid),
+		fmt.Sprintf("/proc/%d/attr/apparmor/current", pid),
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if label := strings.TrimSpace(strings.TrimRight(string(data), "\x00")); label != "" {
+			return label
+		}
+	}
+	return ""
+}
+
+func readSecurityContext(pid int) *model.SecurityContext {
+	status, err := Snapshot().Status(pid)
+	if err != nil {
+		return nil
+	}
+	sec := parseSecurityStatus(string(status))
+	sec.LSMLabel = readLSMLabel(pid)
+	return sec
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package proc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDecodeCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		mask string
+		want []string
+	}{
+		{name: "empty", mask: "0000000000000000", want: []string{}},
+		{name: "net bind service", mask: "0000000000000400", want: []string{"CAP_NET_BIND_SERVICE"}},
+		{name: "sys admin and chown", mask: "0000000000200001", want: []string{"CAP_CHOWN", "CAP_SYS_ADMIN"}},
+		{name: "unknown future bit", mask: "0000800000000000", want: []string{"CAP_47"}},
+		{name: "invalid", mask: "zz", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeCapabilities(tt.mask); !slices.Equal(got, tt.want) {
+				t.Fatalf("decodeCapabilities(%q) = %v, want %v", tt.mask, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSecurityStatus(t *testing.T) {
+	status := "Name:\tnginx\n" +
+		"Uid:\t1000\t33\t33\t33\n" +
+		"CapInh:\t0000000000000000\n" +
+		"CapPrm:\t0000000000000400\n" +
+		"CapEff:\t0000000000000400\n" +
+		"CapBnd:\t000001ffffffffff\n" +
+		"NoNewPrivs:\t1\n" +
+		"Seccomp:\t2\n"
+
+	sec := parseSecurityStatus(status)
+	if sec.UID != 33 {
+		t.Errorf("UID = %d, want 33 (effective)", sec.UID)
+	}
+	if !slices.Equal(sec.CapEff, []string{"CAP_NET_BIND_SERVICE"}) {
+		t.Errorf("CapEff = %v", sec.CapEff)
+	}
+	if len(sec.CapBnd) != 41 {
+		t.Errorf("len(CapBnd) = %d, want 41", len(sec.CapBnd))
+	}
+	if !sec.NoNewPrivs {
+		t.Error("NoNewPrivs = false, want true")
+	}
+	if sec.Seccomp != "filter" {
+		t.Errorf("Seccomp = %q, want filter", sec.Seccomp)
+	}
+}
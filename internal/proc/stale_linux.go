@@ -0,0 +1,209 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const deletedSuffix = " (deleted)"
+
+// readExe resolves /proc/<pid>/exe, reporting whether the binary has been
+// deleted or replaced since the process started
+func readExe(pid int) (string, bool) {
+	exe, err := Snapshot().Readlink(pid, "exe")
+	if err != nil {
+		return "", false
+	}
+	if trimmed, ok := strings.CutSuffix(exe, deletedSuffix); ok {
+		return trimmed, true
+	}
+	return exe, false
+}
+
+// readDeletedLibs lists deleted files still mapped into the process
+func readDeletedLibs(pid int) []string {
+	libs, err := timed(func() ([]string, error) {
+		f, err := os.Open(fmt.Sprintf("/proc/%d/maps", pid))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		var libs []string
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if lib, ok := parseDeletedMapping(scanner.Text()); ok && !slices.Contains(libs, lib) {
+				libs = append(libs, lib)
+			}
+		}
+		return libs, scanner.Err()
+	})
+	if errors.Is(err, errTimedOut) {
+		Snapshot().MarkIncomplete(pid)
+	} else if err != nil {
+		Snapshot().note(pid, err)
+	}
+	sort.Strings(libs)
+	return libs
+}
+
+// parseDeletedMapping extracts the path of a deleted executable mapping from a
+// /proc/<pid>/maps line such as
+//
+//	7f1c2a000000-7f1c2a1a0000 r-xp 00000000 08:01 1234  /usr/lib/libssl.so.3 (deleted)
+//
+// Shared memory, memfd and other anonymous-but-named mappings are ignored,
+// they are always "deleted" and say nothing about upgrades.
+func parseDeletedMapping(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 6 || !strings.HasSuffix(line, deletedSuffix) {
+		return "", false
+	}
+	perms := fields[1]
+	name := strings.TrimSuffix(strings.Join(fields[5:], " "), deletedSuffix)
+	if !strings.HasPrefix(name, "/") {
+		return "", false
+	}
+	for _, prefix := range []string{"/memfd:", "/dev/", "/SYSV", "/run/", "/tmp/", "/var/tmp/", "/[aio]"} {
+		if strings.HasPrefix(name, prefix) {
+			return "", false
+		}
+	}
+	if !strings.Contains(perms, "x") && !strings.Contains(path.Base(name), ".so") {
+		return "", false
+	}
+	return name, true
+}
+
+// deletedOwners looks up the package owning each deleted path. cache, when
+// not nil, carries the lookups across processes.
+func deletedOwners(paths []string, cache map[string]string) map[string]string {
+	var owners map[string]string
+	for _, p := range paths {
+		owner, ok := cache[p]
+		if !ok {
+			owner = PackageOwner(p)
+			if cache != nil {
+				cache[p] = owner
+			}
+		}
+		if owner == "" {
+			continue
+		}
+		if owners == nil {
+			owners = make(map[string]string)
+		}
+		owners[p] = owner
+	}
+	return owners
+}
+
+// systemdUnit derives the system manager's unit of a process from its
+// cgroup path, empty for processes of a user manager (systemd --user)
+func systemdUnit(pid int) string {
+	unit, uid := cgroupUnit(pid)
+	if uid >= 0 {
+		return ""
+	}
+	return unit
+}
+
+// cgroupUnit derives the systemd unit of a process from its cgroup path.
+// Units of a user manager (…/user@1000.service/app.slice/foo.service) come
+// with the uid of its user, system units with -1. The user manager itself
+// and processes outside its services have no unit.
+func cgroupUnit(pid int) (string, int) {
+	data, err := Snapshot().Cgroup(pid)
+	if err != nil {
+		return "", -1
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[1] != "" && parts[1] != "name=systemd" {
+			continue
+		}
+		if unit, uid := parseCgroupUnit(parts[2]); unit != "" || uid >= 0 {
+			return unit, uid
+		}
+	}
+	return "", -1
+}
+
+func parseCgroupUnit(path string) (string, int) {
+	unit, uid := "", -1
+	for _, elem := range strings.Split(path, "/") {
+		if v, ok := strings.CutPrefix(elem, "user@"); ok && strings.HasSuffix(v, ".service") {
+			if n, err := strconv.Atoi(strings.TrimSuffix(v, ".service")); err == nil {
+				unit, uid = "", n
+				continue
+			}
+		}
+		if strings.HasSuffix(elem, ".service") {
+			unit = elem
+		}
+	}
+	return unit, uid
+}
+
+// ListStaleProcesses scans every process for a deleted executable or
+// deleted shared libraries, i.e. processes that need a restart to pick up
+// upgraded binaries. Processes it may not inspect are left out and counted
+// by Snapshot().Denied() and Incomplete().
+func ListStaleProcesses() ([]model.StaleProcess, error) {
+	pids := Snapshot().PIDs()
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("read /proc: no processes found")
+	}
+
+	var stale []model.StaleProcess
+	owners := make(map[string]string)
+	for _, pid := range pids {
+		exe, deleted := readExe(pid)
+		if exe == "" {
+			// Kernel threads, and processes we may not inspect (counted)
+			continue
+		}
+		libs := readDeletedLibs(pid)
+		if deleted {
+			libs = slices.DeleteFunc(libs, func(l string) bool { return l == exe })
+		}
+		if !deleted && len(libs) == 0 {
+			continue
+		}
+		paths := libs
+		if deleted {
+			paths = append([]string{exe}, libs...)
+		}
+		unit, unitUID := cgroupUnit(pid)
+		unitUser := ""
+		if unitUID >= 0 {
+			unitUser = lookupUID(unitUID)
+		}
+		stale = append(stale, model.StaleProcess{
+			PID:           pid,
+			Command:       readComm(pid),
+			User:          readUser(pid),
+			Unit:          unit,
+			UnitUser:      unitUser,
+			Exe:           exe,
+			ExeDeleted:    deleted,
+			DeletedLibs:   libs,
+			DeletedOwners: deletedOwners(paths, owners),
+		})
+	}
+	return stale, nil
+}
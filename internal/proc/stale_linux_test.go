@@ -0,0 +1,47 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestParseDeletedMapping(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+		ok   bool
+	}{
+		{line: "7f1c2a000000-7f1c2a1a0000 r-xp 00000000 08:01 1234                       /usr/lib/x86_64-linux-gnu/libssl.so.3 (deleted)", want: "/usr/lib/x86_64-linux-gnu/libssl.so.3", ok: true},
+		{line: "7f1c2a1a0000-7f1c2a1b0000 r--p 001a0000 08:01 1234                       /usr/lib/x86_64-linux-gnu/libssl.so.3 (deleted)", want: "/usr/lib/x86_64-linux-gnu/libssl.so.3", ok: true},
+		{line: "55d0c0000000-55d0c0100000 r-xp 00000000 08:01 99                         /opt/my app/bin/server (deleted)", want: "/opt/my app/bin/server", ok: true},
+		{line: "7f1c2a000000-7f1c2a1a0000 r-xp 00000000 08:01 1234                       /usr/lib/x86_64-linux-gnu/libssl.so.3", ok: false},
+		{line: "7f1c2a000000-7f1c2a1a0000 rw-s 00000000 00:01 2048                       /dev/shm/pulse-shm-1 (deleted)", ok: false},
+		{line: "7f1c2a000000-7f1c2a1a0000 r-xp 00000000 00:01 2048                       /memfd:jit (deleted)", ok: false},
+		{line: "7f1c2a000000-7f1c2a1a0000 rw-p 00000000 08:01 77                         /var/lib/app/cache.db (deleted)", ok: false},
+		{line: "7ffd1a000000-7ffd1a021000 rw-p 00000000 00:00 0                          [stack]", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDeletedMapping(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseDeletedMapping(%q) = %q, %v; want %q, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseCgroupUnit(t *testing.T) {
+	tests := []struct {
+		path string
+		unit string
+		uid  int
+	}{
+		{path: "/system.slice/nginx.service", unit: "nginx.service", uid: -1},
+		{path: "/system.slice/docker-0123.scope", unit: "", uid: -1},
+		{path: "/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service", unit: "syncthing.service", uid: 1000},
+		{path: "/user.slice/user-1000.slice/user@1000.service/init.scope", unit: "", uid: 1000},
+		{path: "/user.slice/user-1000.slice/session-3.scope", unit: "", uid: -1},
+	}
+	for _, tt := range tests {
+		if unit, uid := parseCgroupUnit(tt.path); unit != tt.unit || uid != tt.uid {
+			t.Errorf("parseCgroupUnit(%q) = %q, %d; want %q, %d", tt.path, unit, uid, tt.unit, tt.uid)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package proc
+
+import (
+	"fmt"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ListStaleProcesses finds processes running deleted binaries (Linux only)
+func ListStaleProcesses() ([]model.StaleProcess, error) {
+	return nil, fmt.Errorf("stale process audit is only supported on Linux")
+}
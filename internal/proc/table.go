@@ -0,0 +1,426 @@
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Table is a snapshot of the process table (/proc) shared by everything one
+// witr invocation looks up. The PID list is read once, and each process's
+// stat, status, cmdline and cgroup the first time something asks for them,
+// so resolving a name, walking the ancestry, listing children and finding
+// socket owners no longer walk /proc each. Values measured over time, such
+// as the CPU ticks for sampling, are still read directly.
+//
+// Every read gives up after readTimeout, so a process stuck in D state (on a
+// hung NFS mount, say) cannot hang witr, and scans over all processes stop
+// at the deadline set with SetDeadline. Processes that could not be read are
+// counted by Incomplete, and by Denied when only permissions kept them out.
+type Table struct {
+	root string
+
+	mu         sync.Mutex
+	pids       []int
+	listed     bool
+	procs      map[int]*tableProc
+	children   map[int][]int
+	deadline   time.Time
+	incomplete map[int]bool
+	denied     map[int]bool
+
+	// Socket index, see table_linux.go
+	owners    map[string][]int
+	listeners map[string]map[string]Socket
+	services  map[string]string
+}
+
+// tableProc holds what has been read so far of one process
+type tableProc struct {
+	mu      sync.Mutex
+	files   map[string]tableFile
+	links   map[string]tableFile
+	fds     []string
+	fdsErr  error
+	fdsRead bool
+}
+
+type tableFile struct {
+	data []byte
+	err  error
+}
+
+var snapshot = NewTable("/proc")
+
+// readTimeout bounds every read of a process's files
+var readTimeout = time.Second
+
+var errTimedOut = errors.New("timed out")
+
+// Snapshot returns the process table shared by this invocation
+func Snapshot() *Table {
+	return snapshot
+}
+
+// NewTable returns an empty table reading processes from root, normally /proc
+func NewTable(root string) *Table {
+	return &Table{
+		root:       root,
+		procs:      make(map[int]*tableProc),
+		incomplete: make(map[int]bool),
+		denied:     make(map[int]bool),
+		listeners:  make(map[string]map[string]Socket),
+		services:   make(map[string]string),
+	}
+}
+
+// SetDeadline stops scans over all processes at d; the processes left are
+// counted as incomplete. A zero d scans everything.
+func (t *Table) SetDeadline(d time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deadline = d
+}
+
+// Expired reports whether the deadline set with SetDeadline has passed
+func (t *Table) Expired() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.deadline.IsZero() && time.Now().After(t.deadline)
+}
+
+// Incomplete returns how many processes could not be fully read because a
+// read failed or timed out, or the deadline passed before they were
+// reached. Processes only hidden by permissions are counted by Denied.
+func (t *Table) Incomplete() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.incomplete)
+}
+
+// Denied returns how many processes a scan was not allowed to read,
+// normally those of other users when not running as root
+func (t *Table) Denied() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.denied)
+}
+
+// MarkIncomplete counts a process whose details were left out
+func (t *Table) MarkIncomplete(pid int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.incomplete[pid] = true
+}
+
+// note counts a failed read of a process
+func (t *Table) note(pid int, err error) {
+	switch {
+	case err == nil, errors.Is(err, fs.ErrNotExist), errors.Is(err, syscall.ESRCH):
+		// Exited meanwhile
+	case errors.Is(err, fs.ErrPermission):
+		t.mu.Lock()
+		t.denied[pid] = true
+		t.mu.Unlock()
+	default:
+		t.MarkIncomplete(pid)
+	}
+}
+
+// timed runs read, giving up after readTimeout. The read is left to finish
+// in the background, a process stuck in the kernel only costs a goroutine.
+func timed[T any](read func() (T, error)) (T, error) {
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := read()
+		done <- result{v, err}
+	}()
+
+	timer := time.NewTimer(readTimeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-timer.C:
+		var zero T
+		return zero, errTimedOut
+	}
+}
+
+func (t *Table) path(pid int, name string) string {
+	return filepath.Join(t.root, strconv.Itoa(pid), name)
+}
+
+// PIDs lists the running processes in ascending order
+func (t *Table) PIDs() []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.listed {
+		entries, _ := os.ReadDir(t.root)
+		for _, e := range entries {
+			if pid, err := strconv.Atoi(e.Name()); err == nil && e.IsDir() {
+				t.pids = append(t.pids, pid)
+			}
+		}
+		sort.Ints(t.pids)
+		t.listed = true
+	}
+	return t.pids
+}
+
+func (t *Table) proc(pid int) *tableProc {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.procs[pid]
+	if !ok {
+		p = &tableProc{files: make(map[string]tableFile), links: make(map[string]tableFile)}
+		t.procs[pid] = p
+	}
+	return p
+}
+
+// file reads a file of a process the first time it is asked for
+func (t *Table) file(pid int, name string) ([]byte, error) {
+	p := t.proc(pid)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f, ok := p.files[name]; ok {
+		return f.data, f.err
+	}
+	data, err := timed(func() ([]byte, error) {
+		return os.ReadFile(t.path(pid, name))
+	})
+	p.files[name] = tableFile{data: data, err: err}
+	t.note(pid, err)
+	return data, err
+}
+
+// Readlink reads a link of a process, such as cwd or exe, the first time it
+// is asked for
+func (t *Table) Readlink(pid int, name string) (string, error) {
+	p := t.proc(pid)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f, ok := p.links[name]; ok {
+		return string(f.data), f.err
+	}
+	link, err := timed(func() (string, error) {
+		return os.Readlink(t.path(pid, name))
+	})
+	p.links[name] = tableFile{data: []byte(link), err: err}
+	t.note(pid, err)
+	return link, err
+}
+
+// Exists reports whether a path under a process's directory exists, such
+// as cwd/nohup.out: looked up through the process's own working directory
+// or root rather than the host's path to it
+func (t *Table) Exists(pid int, name string) bool {
+	_, err := timed(func() (os.FileInfo, error) {
+		return os.Stat(t.path(pid, name))
+	})
+	t.note(pid, err)
+	return err == nil
+}
+
+// FDLinks returns the targets of a process's open file descriptors
+// (socket:[123], pipe:[456], paths), read the first time they are asked for
+func (t *Table) FDLinks(pid int) []string {
+	links, _ := t.fdLinks(pid)
+	return links
+}
+
+func (t *Table) fdLinks(pid int) ([]string, error) {
+	p := t.proc(pid)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.fdsRead {
+		p.fdsRead = true
+		fdDir := t.path(pid, "fd")
+		p.fds, p.fdsErr = timed(func() ([]string, error) {
+			entries, err := os.ReadDir(fdDir)
+			var links []string
+			for _, e := range entries {
+				if link, err := os.Readlink(filepath.Join(fdDir, e.Name())); err == nil {
+					links = append(links, link)
+				}
+			}
+			return links, err
+		})
+		t.note(pid, p.fdsErr)
+	}
+	return p.fds, p.fdsErr
+}
+
+// Scan reads the named files ("fd" for the descriptor links) of every
+// process ahead of a walk over all of them, with a bounded pool of workers,
+// and returns the processes it reached, in PID order. Once the deadline
+// passes the rest are left out of the walk and counted as incomplete.
+func (t *Table) Scan(files ...string) []int {
+	pids := t.PIDs()
+	reached := make([]bool, len(pids))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(2*runtime.GOMAXPROCS(0), 16, max(len(pids), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				pid := pids[i]
+				if t.Expired() {
+					t.MarkIncomplete(pid)
+					continue
+				}
+				reached[i] = true
+				for _, name := range files {
+					var err error
+					if name == "fd" {
+						_, err = t.fdLinks(pid)
+					} else {
+						_, err = t.file(pid, name)
+					}
+					if err != nil {
+						break
+					}
+				}
+			}
+		}()
+	}
+	for i := range pids {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	var scanned []int
+	for i, pid := range pids {
+		if reached[i] {
+			scanned = append(scanned, pid)
+		}
+	}
+	return scanned
+}
+
+// Stat returns the contents of /proc/<pid>/stat
+func (t *Table) Stat(pid int) ([]byte, error) {
+	return t.file(pid, "stat")
+}
+
+// Status returns the contents of /proc/<pid>/status
+func (t *Table) Status(pid int) ([]byte, error) {
+	return t.file(pid, "status")
+}
+
+// Cgroup returns the contents of /proc/<pid>/cgroup
+func (t *Table) Cgroup(pid int) ([]byte, error) {
+	return t.file(pid, "cgroup")
+}
+
+// Cmdline returns the command line with its arguments separated by spaces,
+// empty when it cannot be read or the process is a kernel thread
+func (t *Table) Cmdline(pid int) string {
+	data, err := t.file(pid, "cmdline")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+}
+
+// Argv returns the arguments of the command line as the process got them,
+// nil when it cannot be read or the process is a kernel thread
+func (t *Table) Argv(pid int) []string {
+	data, err := t.file(pid, "cmdline")
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+}
+
+// Comm returns the command name, empty when the process is gone
+func (t *Table) Comm(pid int) string {
+	stat, err := t.Stat(pid)
+	if err != nil {
+		return ""
+	}
+	p, err := parseStatSnapshot(pid, stat)
+	if err != nil {
+		return ""
+	}
+	return p.Command
+}
+
+// PPID returns the parent of a process
+func (t *Table) PPID(pid int) (int, bool) {
+	stat, err := t.Stat(pid)
+	if err != nil {
+		return 0, false
+	}
+	p, err := parseStatSnapshot(pid, stat)
+	if err != nil {
+		return 0, false
+	}
+	return p.PPID, true
+}
+
+// Children returns the direct children of a process in PID order. The
+// parent index is built on first use.
+func (t *Table) Children(pid int) []int {
+	t.mu.Lock()
+	index := t.children
+	t.mu.Unlock()
+	if index == nil {
+		index = make(map[int][]int)
+		for _, child := range t.Scan("stat") {
+			if ppid, ok := t.PPID(child); ok {
+				index[ppid] = append(index[ppid], child)
+			}
+		}
+		t.mu.Lock()
+		t.children = index
+		t.mu.Unlock()
+	}
+	return index[pid]
+}
+
+// parseStatSnapshot reads the command and parent from a stat line
+func parseStatSnapshot(pid int, stat []byte) (model.Process, error) {
+	raw := string(stat)
+	open := strings.Index(raw, "(")
+	close := strings.LastIndex(raw, ")")
+	if open == -1 || close == -1 || close <= open || close+2 > len(raw) {
+		return model.Process{}, fmt.Errorf("invalid stat format")
+	}
+
+	comm := raw[open+1 : close]
+	fields := strings.Fields(raw[close+2:])
+	if len(fields) < 2 {
+		return model.Process{}, fmt.Errorf("invalid stat format")
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return model.Process{}, fmt.Errorf("invalid ppid")
+	}
+
+	return model.Process{
+		PID:     pid,
+		PPID:    ppid,
+		Command: comm,
+	}, nil
+}
@@ -0,0 +1,115 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SocketInodes returns the inodes of the sockets a process has open
+func (t *Table) SocketInodes(pid int) []string {
+	var inodes []string
+	for _, link := range t.FDLinks(pid) {
+		if rest, ok := strings.CutPrefix(link, "socket:["); ok {
+			if inode, ok := strings.CutSuffix(rest, "]"); ok && !slices.Contains(inodes, inode) {
+				inodes = append(inodes, inode)
+			}
+		}
+	}
+	return inodes
+}
+
+// SocketOwners returns the processes holding any of the socket inodes, in
+// PID order. The inode index over all processes is built on first use.
+func (t *Table) SocketOwners(inodes map[string]bool) []int {
+	t.mu.Lock()
+	index := t.owners
+	t.mu.Unlock()
+	if index == nil {
+		index = make(map[string][]int)
+		for _, pid := range t.Scan("fd") {
+			for _, inode := range t.SocketInodes(pid) {
+				index[inode] = append(index[inode], pid)
+			}
+		}
+		t.mu.Lock()
+		t.owners = index
+		t.mu.Unlock()
+	}
+
+	var owners []int
+	for inode := range inodes {
+		for _, pid := range index[inode] {
+			if !slices.Contains(owners, pid) {
+				owners = append(owners, pid)
+			}
+		}
+	}
+	slices.Sort(owners)
+	return owners
+}
+
+// Listeners returns the TCP listeners visible in the network namespace of a
+// process, read once per namespace
+func (t *Table) Listeners(pid int) map[string]Socket {
+	key, err := os.Readlink(t.path(pid, "ns/net"))
+	if err != nil {
+		key = strconv.Itoa(pid)
+	}
+	t.mu.Lock()
+	sockets, ok := t.listeners[key]
+	t.mu.Unlock()
+	if !ok {
+		sockets = readListeningSockets(t.path(pid, "net"))
+		t.mu.Lock()
+		t.listeners[key] = sockets
+		t.mu.Unlock()
+	}
+	return sockets
+}
+
+// Service asks systemctl which service a process belongs to. systemd goes by
+// the cgroup, so the answer is shared by every process in it, and processes
+// outside any .service cgroup need no fork at all.
+func (t *Table) Service(pid int) string {
+	cgroup, err := t.Cgroup(pid)
+	if err != nil || !strings.Contains(string(cgroup), ".service") {
+		return ""
+	}
+	t.mu.Lock()
+	service, ok := t.services[string(cgroup)]
+	t.mu.Unlock()
+	if ok {
+		return service
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemctlTimeout)
+	svcOut, err := exec.CommandContext(ctx, "systemctl", "status", strconv.Itoa(pid)).CombinedOutput()
+	if ctx.Err() != nil {
+		t.MarkIncomplete(pid)
+	}
+	cancel()
+	if err == nil && strings.Contains(string(svcOut), "Loaded: loaded") {
+		// Try to extract service name from output
+		for line := range strings.Lines(string(svcOut)) {
+			if strings.HasPrefix(line, "Loaded:") && strings.Contains(line, ".service") {
+				parts := strings.Fields(line)
+				for _, part := range parts {
+					if strings.HasSuffix(part, ".service") {
+						service = part
+						break
+					}
+				}
+			}
+		}
+	}
+	t.mu.Lock()
+	t.services[string(cgroup)] = service
+	t.mu.Unlock()
+	return service
+}
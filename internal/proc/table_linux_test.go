@@ -0,0 +1,225 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeFakeProc adds a process to a synthetic /proc under root
+func writeFakeProc(tb testing.TB, root string, pid, ppid int, comm string, inodes ...int) {
+	tb.Helper()
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	if err := os.MkdirAll(filepath.Join(dir, "fd"), 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	stat := fmt.Sprintf("%d (%s) S %d %d %d 0 -1 4194560 0 0 0 0 1 2 0 0 20 0 1 0 100 0 0\n", pid, comm, ppid, pid, pid)
+	files := map[string]string{
+		"stat":    stat,
+		"cmdline": "/usr/bin/" + comm + "\x00--serve\x00",
+		"cgroup":  "0::/system.slice/" + comm + ".service\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	for i, inode := range inodes {
+		link := filepath.Join(dir, "fd", fmt.Sprint(i+3))
+		if err := os.Symlink(fmt.Sprintf("socket:[%d]", inode), link); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProc(t, root, 1, 0, "init")
+	writeFakeProc(t, root, 20, 1, "nginx", 500, 501)
+	writeFakeProc(t, root, 21, 20, "nginx", 500)
+	writeFakeProc(t, root, 3, 1, "sshd", 600)
+	if err := os.MkdirAll(filepath.Join(root, "self"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTable(root)
+	if got := table.PIDs(); !slices.Equal(got, []int{1, 3, 20, 21}) {
+		t.Errorf("PIDs() = %v", got)
+	}
+	if got := table.Children(1); !slices.Equal(got, []int{3, 20}) {
+		t.Errorf("Children(1) = %v", got)
+	}
+	if got := table.Children(21); got != nil {
+		t.Errorf("Children(21) = %v, want none", got)
+	}
+	if ppid, ok := table.PPID(21); !ok || ppid != 20 {
+		t.Errorf("PPID(21) = %d, %v", ppid, ok)
+	}
+	if got := table.Comm(3); got != "sshd" {
+		t.Errorf("Comm(3) = %q", got)
+	}
+	if got := table.Cmdline(20); got != "/usr/bin/nginx --serve" {
+		t.Errorf("Cmdline(20) = %q", got)
+	}
+	if got := table.Argv(20); !slices.Equal(got, []string{"/usr/bin/nginx", "--serve"}) {
+		t.Errorf("Argv(20) = %q", got)
+	}
+	if got := table.SocketInodes(20); !slices.Equal(got, []string{"500", "501"}) {
+		t.Errorf("SocketInodes(20) = %v", got)
+	}
+	if got := table.SocketOwners(map[string]bool{"500": true}); !slices.Equal(got, []int{20, 21}) {
+		t.Errorf("SocketOwners(500) = %v", got)
+	}
+	if got := table.SocketOwners(map[string]bool{"999": true}); got != nil {
+		t.Errorf("SocketOwners(999) = %v, want none", got)
+	}
+
+	// What was read stays as it was for the rest of the invocation
+	if err := os.RemoveAll(filepath.Join(root, "20")); err != nil {
+		t.Fatal(err)
+	}
+	if got := table.Comm(20); got != "nginx" {
+		t.Errorf("Comm(20) after exit = %q, want the snapshot", got)
+	}
+	if got := NewTable(root).Comm(20); got != "" {
+		t.Errorf("new table Comm(20) = %q, want empty", got)
+	}
+	if _, err := table.Status(20); err == nil {
+		t.Error("Status(20) of an unread, exited process succeeded")
+	}
+}
+
+func TestTableScan(t *testing.T) {
+	root := t.TempDir()
+	for pid := 1; pid <= 6; pid++ {
+		writeFakeProc(t, root, pid, 1, "worker", 700+pid)
+	}
+	// 2 cannot be listed, 3 hangs on its command line, 4 exits
+	fdDir := filepath.Join(root, "2", "fd")
+	if err := os.RemoveAll(fdDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fdDir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fifo := filepath.Join(root, "3", "cmdline")
+	if err := os.Remove(fifo); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Mkfifo(fifo, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// Let the abandoned read finish
+		if f, err := os.OpenFile(fifo, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+			f.Close()
+		}
+	})
+	defer func(d time.Duration) { readTimeout = d }(readTimeout)
+	readTimeout = 50 * time.Millisecond
+
+	table := NewTable(root)
+	table.PIDs()
+	if err := os.RemoveAll(filepath.Join(root, "4")); err != nil {
+		t.Fatal(err)
+	}
+	if got := table.Scan("stat", "cmdline", "fd"); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Scan() = %v", got)
+	}
+	if got := table.Incomplete(); got != 2 {
+		t.Errorf("Incomplete() = %d, want 2", got)
+	}
+	// Other users' processes are not counted as incomplete
+	table.note(5, &fs.PathError{Op: "open", Path: "fd", Err: syscall.EACCES})
+	if got, denied := table.Incomplete(), table.Denied(); got != 2 || denied != 1 {
+		t.Errorf("Incomplete(), Denied() = %d, %d, want 2, 1", got, denied)
+	}
+	if got := table.Cmdline(3); got != "" {
+		t.Errorf("Cmdline(3) = %q, want empty after timing out", got)
+	}
+	if got := table.SocketOwners(map[string]bool{"706": true}); !slices.Equal(got, []int{6}) {
+		t.Errorf("SocketOwners(706) = %v", got)
+	}
+
+	late := NewTable(root)
+	late.SetDeadline(time.Now().Add(-time.Second))
+	if got := late.Scan("stat"); got != nil {
+		t.Errorf("Scan() past the deadline = %v, want none", got)
+	}
+	if got := late.Incomplete(); got != 5 {
+		t.Errorf("Incomplete() past the deadline = %d, want 5", got)
+	}
+	// Lookups of single processes, such as the ancestry, still read them
+	if got := late.Comm(1); got != "worker" {
+		t.Errorf("Comm(1) past the deadline = %q, want worker", got)
+	}
+}
+
+// BenchmarkTable resolves a name, walks the ancestry, lists children twice
+// and finds a socket's owner over a synthetic /proc of 12,000 processes,
+// once with a table shared by every lookup and once with a new table per
+// lookup. The second is a simulation of the resolvers each walking /proc on
+// their own, not the old code path: that also re-read /proc/net/tcp and
+// forked systemctl per ancestor, which the synthetic /proc cannot model, so
+// it understates the difference.
+func BenchmarkTable(b *testing.B) {
+	const procs = 12000
+	root := b.TempDir()
+	for pid := 1; pid <= procs; pid++ {
+		ppid := 0
+		if pid > 1 {
+			ppid = pid / 4
+			if ppid == 0 {
+				ppid = 1
+			}
+		}
+		writeFakeProc(b, root, pid, ppid, fmt.Sprintf("worker-%d", pid%50), 100000+pid)
+	}
+	target := procs - 1
+
+	resolve := func(tables func() *Table) {
+		var matched []int
+		t := tables()
+		for _, pid := range t.PIDs() {
+			if t.Comm(pid) == "worker-49" || strings.Contains(t.Cmdline(pid), "worker-49 ") {
+				matched = append(matched, pid)
+			}
+		}
+		if len(matched) == 0 {
+			b.Fatal("no process matched")
+		}
+		for pid := target; pid > 0; {
+			ppid, ok := tables().PPID(pid)
+			if !ok {
+				break
+			}
+			pid = ppid
+		}
+		tables().Children(target / 4)
+		tables().Children(target / 4)
+		owners := tables().SocketOwners(map[string]bool{fmt.Sprint(100000 + target): true})
+		if len(owners) != 1 {
+			b.Fatalf("SocketOwners() = %v", owners)
+		}
+	}
+
+	b.Run("shared", func(b *testing.B) {
+		for b.Loop() {
+			shared := NewTable(root)
+			resolve(func() *Table { return shared })
+		}
+	})
+	b.Run("simulated-per-lookup", func(b *testing.B) {
+		for b.Loop() {
+			resolve(func() *Table { return NewTable(root) })
+		}
+	})
+}
@@ -0,0 +1,126 @@
+//go:build linux
+
+package proc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var unitProperties = []string{
+	"Id", "LoadState", "ActiveState", "SubState", "Result", "MainPID",
+	"ExecMainPID", "ExecMainCode", "ExecMainStatus",
+	"ExecMainStartTimestamp", "ExecMainExitTimestamp", "StateChangeTimestamp", "NRestarts",
+}
+
+// execMainCodes are the si_code values systemctl reports as ExecMainCode
+var execMainCodes = map[string]string{"1": "exited", "2": "killed", "3": "dumped"}
+
+// systemctlTimeout bounds each systemctl call, so a wedged systemd cannot
+// hang witr
+const systemctlTimeout = 5 * time.Second
+
+func systemctlShow(args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), systemctlTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, "systemctl", append([]string{"show"}, args...)...).Output()
+}
+
+// ReadUnitState asks systemd for the state of a unit and how its main
+// process last ended
+func ReadUnitState(unit string) (*model.UnitState, error) {
+	props := strings.Join(unitProperties, ",")
+	out, err := systemctlShow("--timestamp=unix", "-p", props, "--", unit)
+	if err != nil {
+		// --timestamp needs systemd 248
+		out, err = systemctlShow("-p", props, "--", unit)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			msg, _, _ := strings.Cut(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+			return nil, fmt.Errorf("systemctl show %s: %s", unit, msg)
+		}
+		return nil, fmt.Errorf("systemctl show %s: %w", unit, err)
+	}
+	state := parseUnitState(string(out))
+	if state.LoadState == "not-found" {
+		return nil, fmt.Errorf("unit %s not found", unit)
+	}
+	return state, nil
+}
+
+// UnitRestarts returns how often systemd has automatically restarted the
+// service a process runs in (NRestarts, since the unit was last started by hand)
+func UnitRestarts(pid int) (count int, unit string, ok bool) {
+	unit = systemdUnit(pid)
+	if unit == "" {
+		return 0, "", false
+	}
+	state, err := ReadUnitState(unit)
+	if err != nil {
+		return 0, "", false
+	}
+	return state.NRestarts, unit, true
+}
+
+// parseUnitState reads the Key=Value lines of systemctl show
+func parseUnitState(out string) *model.UnitState {
+	s := &model.UnitState{}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Id":
+			s.Name = value
+		case "LoadState":
+			s.LoadState = value
+		case "ActiveState":
+			s.ActiveState = value
+		case "SubState":
+			s.SubState = value
+		case "Result":
+			s.Result = value
+		case "MainPID":
+			s.MainPID, _ = strconv.Atoi(value)
+		case "ExecMainPID":
+			s.ExecMainPID, _ = strconv.Atoi(value)
+		case "ExecMainCode":
+			s.ExecMainCode = execMainCodes[value]
+		case "ExecMainStatus":
+			s.ExecMainStatus, _ = strconv.Atoi(value)
+		case "ExecMainStartTimestamp":
+			s.ExecMainStart = parseSystemdTime(value)
+		case "ExecMainExitTimestamp":
+			s.ExecMainExit = parseSystemdTime(value)
+		case "StateChangeTimestamp":
+			s.StateChange = parseSystemdTime(value)
+		case "NRestarts":
+			s.NRestarts, _ = strconv.Atoi(value)
+		}
+	}
+	return s
+}
+
+// parseSystemdTime parses "@1760853412" (--timestamp=unix) or
+// "Sun 2025-10-19 05:56:52 UTC"; empty and "n/a" are the zero time
+func parseSystemdTime(value string) time.Time {
+	if sec, ok := strings.CutPrefix(value, "@"); ok {
+		if n, err := strconv.ParseInt(sec, 10, 64); err == nil {
+			return time.Unix(n, 0)
+		}
+	}
+	if t, err := time.Parse("Mon 2006-01-02 15:04:05 MST", value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,38 @@
+//go:build linux
+
+package proc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUnitState(t *testing.T) {
+	out := `Id=app.service
+LoadState=loaded
+ActiveState=failed
+SubState=failed
+Result=signal
+MainPID=0
+ExecMainPID=4242
+ExecMainCode=2
+ExecMainStatus=9
+ExecMainStartTimestamp=@1760853000
+ExecMainExitTimestamp=@1760853412
+StateChangeTimestamp=Sun 2025-10-19 05:56:52 UTC
+NRestarts=3
+`
+	s := parseUnitState(out)
+	if s.Name != "app.service" || s.ActiveState != "failed" || s.Result != "signal" || s.NRestarts != 3 {
+		t.Errorf("parseUnitState() = %+v", s)
+	}
+	if s.ExecMainPID != 4242 || s.ExecMainCode != "killed" || s.ExecMainStatus != 9 {
+		t.Errorf("main process = pid %d %s %d, want pid 4242 killed 9", s.ExecMainPID, s.ExecMainCode, s.ExecMainStatus)
+	}
+	if !s.ExecMainExit.Equal(time.Unix(1760853412, 0)) {
+		t.Errorf("ExecMainExit = %v", s.ExecMainExit)
+	}
+	if want := time.Date(2025, 10, 19, 5, 56, 52, 0, time.UTC); !s.StateChange.Equal(want) {
+		t.Errorf("StateChange = %v, want %v", s.StateChange, want)
+	}
+}
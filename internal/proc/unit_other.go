@@ -0,0 +1,30 @@
+//go:build !linux
+
+package proc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ReadUnitState queries systemd (Linux only)
+func ReadUnitState(unit string) (*model.UnitState, error) {
+	return nil, fmt.Errorf("systemd units are only supported on Linux")
+}
+
+// UnitRestarts queries systemd (Linux only)
+func UnitRestarts(pid int) (count int, unit string, ok bool) {
+	return 0, "", false
+}
+
+// JournalUnitState reads the systemd journal (Linux only)
+func JournalUnitState(unit string) *model.UnitState {
+	return nil
+}
+
+// JournalUnitOf reads the systemd journal (Linux only)
+func JournalUnitOf(pid int, since time.Time) string {
+	return ""
+}
@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+
+	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func readUser(pid int) string {
@@ -22,11 +24,14 @@ func readUser(pid int) string {
 		return "unknown"
 	}
 
-	uid := int(stat.Uid)
+	return lookupUID(int(stat.Uid))
+}
+
+// lookupUID resolves a uid to a user name from /etc/passwd
+func lookupUID(uid int) string {
 	if uid == 0 {
 		return "root"
 	}
-	// Try to resolve username from /etc/passwd
 	uidStr := strconv.Itoa(uid)
 	passwd, err := os.ReadFile("/etc/passwd")
 	if err == nil {
@@ -39,3 +44,27 @@ func readUser(pid int) string {
 	}
 	return uidStr
 }
+
+// unsetLoginUID is the loginuid of processes not started from a login
+// (services, kernel threads); (uid_t)-1
+const unsetLoginUID = 4294967295
+
+// readLogin reads the audit login uid and session id of a process
+func readLogin(pid int) *model.LoginInfo {
+	table := Snapshot()
+	data, err := table.file(pid, "loginuid")
+	if err != nil {
+		return nil
+	}
+	uid, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil || uid == unsetLoginUID {
+		return nil
+	}
+	login := &model.LoginInfo{UID: int(uid), User: lookupUID(int(uid))}
+	if data, err := table.file(pid, "sessionid"); err == nil {
+		if id, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32); err == nil && id != unsetLoginUID {
+			login.SessionID = int(id)
+		}
+	}
+	return login
+}
@@ -0,0 +1,183 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Suppression is a single allowlist entry. Rule is required; every other
+// matcher that is set must match for the warning to be suppressed.
+type Suppression struct {
+	Rule    string `json:"rule"`
+	Unit    string `json:"unit,omitempty"`
+	Exe     string `json:"exe,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Port    int    `json:"port,omitempty"`
+	Expires string `json:"expires,omitempty"` // YYYY-MM-DD, inclusive
+	Reason  string `json:"reason,omitempty"`
+
+	expires time.Time
+}
+
+// Allowlist holds expected warnings that should not be surfaced
+type Allowlist struct {
+	Suppressions []Suppression `json:"suppressions"`
+}
+
+// AllowlistSubject is what suppression matchers are evaluated against
+type AllowlistSubject struct {
+	Units []string
+	Exe   string
+	Image string
+	Ports []int
+}
+
+// DefaultAllowlistPath returns the per-user allowlist location
+// (e.g. ~/.config/witr/allowlist.json on Linux)
+func DefaultAllowlistPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "witr", "allowlist.json")
+}
+
+// LoadAllowlist reads and validates an allowlist file
+func LoadAllowlist(file string) (*Allowlist, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var a Allowlist
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parse allowlist %s: %w", file, err)
+	}
+
+	for i := range a.Suppressions {
+		s := &a.Suppressions[i]
+		if s.Rule == "" {
+			return nil, fmt.Errorf("allowlist %s: entry %d has no rule", file, i+1)
+		}
+		if s.Expires != "" {
+			t, err := time.ParseInLocation("2006-01-02", s.Expires, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("allowlist %s: entry %d has invalid expires %q (want YYYY-MM-DD)", file, i+1, s.Expires)
+			}
+			s.expires = t.AddDate(0, 0, 1)
+		}
+	}
+
+	return &a, nil
+}
+
+// LoadDefaultAllowlist loads the default allowlist if one exists
+func LoadDefaultAllowlist() (*Allowlist, error) {
+	file := DefaultAllowlistPath()
+	if file == "" {
+		return nil, nil
+	}
+	a, err := LoadAllowlist(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return a, err
+}
+
+// NeedsImage reports whether any entry matches on container image, so callers
+// can skip the (comparatively expensive) image lookup otherwise
+func (a *Allowlist) NeedsImage() bool {
+	if a == nil {
+		return false
+	}
+	for _, s := range a.Suppressions {
+		if s.Image != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply splits warnings into the ones to surface and the ones suppressed by the allowlist.
+// Suppressed warnings carry the entry's reason.
+func (a *Allowlist) Apply(warnings []model.Warning, subj AllowlistSubject, now time.Time) ([]model.Warning, []model.Warning) {
+	if a == nil || len(a.Suppressions) == 0 {
+		return warnings, nil
+	}
+
+	var kept, suppressed []model.Warning
+	for _, w := range warnings {
+		if s := a.match(w, subj, now); s != nil {
+			w.SuppressReason = s.Reason
+			if w.SuppressReason == "" {
+				w.SuppressReason = "allowlisted"
+			}
+			suppressed = append(suppressed, w)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return kept, suppressed
+}
+
+func (a *Allowlist) match(w model.Warning, subj AllowlistSubject, now time.Time) *Suppression {
+	for i := range a.Suppressions {
+		s := &a.Suppressions[i]
+		if s.Rule != w.ID && s.Rule != "*" {
+			continue
+		}
+		if !s.expires.IsZero() && !now.Before(s.expires) {
+			continue
+		}
+		if s.Unit != "" && !slices.ContainsFunc(subj.Units, func(u string) bool { return matchUnit(s.Unit, u) }) {
+			continue
+		}
+		if s.Exe != "" && !matchExe(s.Exe, subj.Exe) {
+			continue
+		}
+		if s.Image != "" && !globMatch(s.Image, subj.Image) {
+			continue
+		}
+		if s.Port != 0 && !slices.Contains(subj.Ports, s.Port) {
+			continue
+		}
+		return s
+	}
+	return nil
+}
+
+// matchUnit allows "nginx" to match "nginx.service"
+func matchUnit(pattern, unit string) bool {
+	if unit == "" {
+		return false
+	}
+	return globMatch(pattern, unit) || globMatch(pattern, strings.TrimSuffix(unit, ".service"))
+}
+
+// matchExe matches full paths, or only the base name when the pattern has no slash
+func matchExe(pattern, exe string) bool {
+	if exe == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "/") {
+		return globMatch(pattern, path.Base(exe))
+	}
+	return globMatch(pattern, exe)
+}
+
+func globMatch(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
@@ -0,0 +1,109 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeAllowlist(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "allowlist.json")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestAllowlistApply(t *testing.T) {
+	file := writeAllowlist(t, `{
+  "suppressions": [
+    {"rule": "root-user", "unit": "node_exporter", "reason": "needs root for textfile collector"},
+    {"rule": "public-bind", "exe": "nginx", "port": 443},
+    {"rule": "long-running", "expires": "2020-01-01"}
+  ]
+}`)
+	a, err := LoadAllowlist(file)
+	if err != nil {
+		t.Fatalf("LoadAllowlist: %v", err)
+	}
+
+	warnings := []model.Warning{
+		{ID: "root-user", Severity: model.SeverityInfo, Message: "root"},
+		{ID: "public-bind", Severity: model.SeverityWarn, Message: "public"},
+		{ID: "long-running", Severity: model.SeverityInfo, Message: "old"},
+	}
+
+	tests := []struct {
+		name           string
+		subj           AllowlistSubject
+		wantKept       []string
+		wantSuppressed []string
+	}{
+		{
+			name:           "unit and exe with port match",
+			subj:           AllowlistSubject{Units: []string{"node_exporter.service"}, Exe: "/usr/sbin/nginx", Ports: []int{80, 443}},
+			wantKept:       []string{"old"},
+			wantSuppressed: []string{"root", "public"},
+		},
+		{
+			name:           "port not matched",
+			subj:           AllowlistSubject{Units: []string{"node_exporter.service"}, Exe: "/usr/sbin/nginx", Ports: []int{80}},
+			wantKept:       []string{"public", "old"},
+			wantSuppressed: []string{"root"},
+		},
+		{
+			name:     "nothing matches",
+			subj:     AllowlistSubject{Units: []string{"redis.service"}, Exe: "/usr/bin/redis-server"},
+			wantKept: []string{"root", "public", "old"},
+		},
+	}
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, suppressed := a.Apply(warnings, tt.subj, now)
+			if got := warningMessages(kept); !slices.Equal(got, tt.wantKept) {
+				t.Fatalf("kept = %v, want %v", got, tt.wantKept)
+			}
+			if got := warningMessages(suppressed); !slices.Equal(got, tt.wantSuppressed) {
+				t.Fatalf("suppressed = %v, want %v", got, tt.wantSuppressed)
+			}
+		})
+	}
+}
+
+func TestAllowlistExpiry(t *testing.T) {
+	file := writeAllowlist(t, `{"suppressions": [{"rule": "root-user", "expires": "2025-06-01"}]}`)
+	a, err := LoadAllowlist(file)
+	if err != nil {
+		t.Fatalf("LoadAllowlist: %v", err)
+	}
+	warnings := []model.Warning{{ID: "root-user", Message: "root"}}
+
+	// the expiry date itself is still covered
+	if _, suppressed := a.Apply(warnings, AllowlistSubject{}, time.Date(2025, 6, 1, 23, 0, 0, 0, time.Local)); len(suppressed) != 1 {
+		t.Fatalf("expected warning suppressed on expiry date, got %v", suppressed)
+	}
+	if kept, _ := a.Apply(warnings, AllowlistSubject{}, time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)); len(kept) != 1 {
+		t.Fatalf("expected warning kept after expiry, got %v", kept)
+	}
+}
+
+func TestLoadAllowlistRejectsInvalidEntries(t *testing.T) {
+	for name, content := range map[string]string{
+		"missing rule": `{"suppressions": [{"unit": "nginx"}]}`,
+		"bad expiry":   `{"suppressions": [{"rule": "root-user", "expires": "tomorrow"}]}`,
+		"bad json":     `{"suppressions": [`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadAllowlist(writeAllowlist(t, content)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
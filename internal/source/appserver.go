@@ -0,0 +1,268 @@
+package source
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// appServer recognises a language-level process manager or app server
+// (gunicorn, puma, php-fpm, foreman, ...) from the processes it spawns.
+//
+// match reports whether a process belongs to the server. worker extracts
+// details a worker exposes in its process title (index, pool) and config
+// extracts the configuration from the master's cmdline or environment.
+type appServer struct {
+	name   string
+	match  func(p model.Process) bool
+	worker func(p model.Process) map[string]string
+	config func(master model.Process) map[string]string
+}
+
+var (
+	uwsgiWorkerPattern   = regexp.MustCompile(`^uWSGI worker (\d+)`)
+	celeryWorkerPattern  = regexp.MustCompile(`ForkPoolWorker-(\d+)`)
+	pumaWorkerPattern    = regexp.MustCompile(`^puma: cluster worker (\d+):`)
+	unicornWorkerPattern = regexp.MustCompile(`^unicorn(?:_rails)? worker\[(\d+)\]`)
+	phpFpmPoolPattern    = regexp.MustCompile(`^php-fpm: pool (\S+)`)
+	phpFpmMasterPattern  = regexp.MustCompile(`^php-fpm: master process \(([^)]+)\)`)
+)
+
+var appServers = []appServer{
+	{
+		name:  "gunicorn",
+		match: func(p model.Process) bool { return runs(p, "gunicorn") || strings.HasPrefix(p.Cmdline, "gunicorn: ") },
+		config: func(m model.Process) map[string]string {
+			cfg := argValue(m.Cmdline, "-c", "--config")
+			if cfg == "" {
+				cfg = argValue(envValue(m.Env, "GUNICORN_CMD_ARGS"), "-c", "--config")
+			}
+			return details("config", cfg)
+		},
+	},
+	{
+		name:  "uwsgi",
+		match: func(p model.Process) bool { return runs(p, "uwsgi") || strings.HasPrefix(p.Cmdline, "uWSGI ") },
+		worker: func(p model.Process) map[string]string {
+			return details("worker", submatch(uwsgiWorkerPattern, p.Cmdline))
+		},
+		config: func(m model.Process) map[string]string {
+			cfg := argValue(m.Cmdline, "--ini", "--yaml", "--yml", "--json", "--xml")
+			if cfg == "" {
+				for _, arg := range strings.Fields(m.Cmdline)[1:] {
+					if ext := path.Ext(arg); ext == ".ini" || ext == ".yaml" || ext == ".yml" || ext == ".xml" {
+						cfg = arg
+						break
+					}
+				}
+			}
+			if cfg == "" {
+				cfg = envValue(m.Env, "UWSGI_INI")
+			}
+			return details("config", cfg)
+		},
+	},
+	{
+		name:  "celery",
+		match: func(p model.Process) bool { return runs(p, "celery") || strings.Contains(p.Cmdline, "[celeryd:") },
+		worker: func(p model.Process) map[string]string {
+			return details("worker", submatch(celeryWorkerPattern, p.Cmdline))
+		},
+		config: func(m model.Process) map[string]string {
+			return details("app", argValue(m.Cmdline, "-A", "--app"), "config", argValue(m.Cmdline, "--config"))
+		},
+	},
+	{
+		name:  "sidekiq",
+		match: func(p model.Process) bool { return runs(p, "sidekiq") || strings.HasPrefix(p.Cmdline, "sidekiq ") },
+		config: func(m model.Process) map[string]string {
+			return details("config", argValue(m.Cmdline, "-C", "--config"))
+		},
+	},
+	{
+		name: "puma",
+		match: func(p model.Process) bool {
+			return runs(p, "puma") || strings.HasPrefix(p.Cmdline, "puma ") || strings.HasPrefix(p.Cmdline, "puma: ")
+		},
+		worker: func(p model.Process) map[string]string {
+			return details("worker", submatch(pumaWorkerPattern, p.Cmdline))
+		},
+		config: func(m model.Process) map[string]string {
+			return details("config", argValue(m.Cmdline, "-C", "--config"))
+		},
+	},
+	{
+		name:  "php-fpm",
+		match: func(p model.Process) bool { return strings.HasPrefix(p.Command, "php-fpm") },
+		worker: func(p model.Process) map[string]string {
+			return details("pool", submatch(phpFpmPoolPattern, p.Cmdline))
+		},
+		config: func(m model.Process) map[string]string {
+			cfg := submatch(phpFpmMasterPattern, m.Cmdline)
+			if cfg == "" {
+				cfg = argValue(m.Cmdline, "-y", "--fpm-config")
+			}
+			return details("config", cfg)
+		},
+	},
+	{
+		name: "unicorn",
+		match: func(p model.Process) bool {
+			return runs(p, "unicorn") || runs(p, "unicorn_rails") || strings.HasPrefix(p.Cmdline, "unicorn ") || strings.HasPrefix(p.Cmdline, "unicorn_rails ")
+		},
+		worker: func(p model.Process) map[string]string {
+			return details("worker", submatch(unicornWorkerPattern, p.Cmdline))
+		},
+		config: func(m model.Process) map[string]string {
+			return details("config", argValue(m.Cmdline, "-c", "--config-file"))
+		},
+	},
+	{
+		name:  "nodemon",
+		match: func(p model.Process) bool { return runs(p, "nodemon") },
+		config: func(m model.Process) map[string]string {
+			return details("config", argValue(m.Cmdline, "--config"))
+		},
+	},
+	{
+		name: "ts-node-dev",
+		match: func(p model.Process) bool {
+			return runs(p, "ts-node-dev") || strings.Contains(p.Cmdline, "/ts-node-dev/")
+		},
+	},
+	{name: "foreman", match: func(p model.Process) bool { return runs(p, "foreman") }, worker: procfileProcess, config: procfileConfig},
+	{name: "overmind", match: func(p model.Process) bool { return runs(p, "overmind") }, worker: procfileProcess, config: procfileConfig},
+	{name: "honcho", match: func(p model.Process) bool { return runs(p, "honcho") }, worker: procfileProcess, config: procfileConfig},
+}
+
+// detectAppServer finds the outermost app server process in the ancestry
+// (its master) and describes the target's role within it
+func detectAppServer(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	for _, srv := range appServers {
+		masterIdx := -1
+		for i, p := range ancestry {
+			if srv.match(p) {
+				masterIdx = i
+				break
+			}
+		}
+		if masterIdx == -1 {
+			continue
+		}
+		master := ancestry[masterIdx]
+
+		d := map[string]string{"master": strconv.Itoa(master.PID)}
+		switch {
+		case masterIdx == len(ancestry)-1:
+			d["role"] = "master"
+		case srv.match(target):
+			d["role"] = "worker"
+		default:
+			d["role"] = "child"
+		}
+		if srv.worker != nil {
+			// The worker title is on the target, or on the nearest worker above it
+			for i := len(ancestry) - 1; i > masterIdx; i-- {
+				if w := srv.worker(ancestry[i]); len(w) > 0 {
+					mergeDetails(d, w)
+					break
+				}
+			}
+		}
+		if srv.config != nil {
+			mergeDetails(d, srv.config(master))
+		}
+
+		return &model.Source{
+			Type:    model.SourceSupervisor,
+			Name:    srv.name,
+			Details: d,
+		}
+	}
+	return nil
+}
+
+// runs reports whether a process executes the named program, either directly
+// (argv[0]) or through an interpreter (python -m celery, node .../nodemon.js)
+func runs(p model.Process, name string) bool {
+	fields := strings.Fields(p.Cmdline)
+	if len(fields) > 0 && path.Base(fields[0]) == name {
+		return true
+	}
+	if p.App != "" {
+		app, _, _ := strings.Cut(p.App, " ")
+		return strings.TrimSuffix(path.Base(app), ".js") == name
+	}
+	return false
+}
+
+// procfileProcess names the Procfile entry (e.g. "web.1") foreman and honcho export as PS
+func procfileProcess(p model.Process) map[string]string {
+	return details("process", envValue(p.Env, "PS"))
+}
+
+func procfileConfig(m model.Process) map[string]string {
+	procfile := argValue(m.Cmdline, "-f", "--procfile")
+	if procfile == "" && m.WorkingDir != "" && m.WorkingDir != "unknown" {
+		procfile = path.Join(m.WorkingDir, "Procfile")
+	}
+	return details("procfile", procfile)
+}
+
+// argValue returns the value of the first matching option in a command line,
+// accepting both "-c value" and "--config=value"
+func argValue(cmdline string, names ...string) string {
+	fields := strings.Fields(cmdline)
+	for i, f := range fields {
+		for _, name := range names {
+			if f == name && i+1 < len(fields) {
+				return fields[i+1]
+			}
+			if v, ok := strings.CutPrefix(f, name+"="); ok && strings.HasPrefix(name, "--") {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func envValue(env []string, key string) string {
+	for _, e := range env {
+		if v, ok := strings.CutPrefix(e, key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func submatch(re *regexp.Regexp, s string) string {
+	if m := re.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// details builds a map from key/value pairs, dropping empty values
+func details(kv ...string) map[string]string {
+	d := make(map[string]string)
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			d[kv[i]] = kv[i+1]
+		}
+	}
+	return d
+}
+
+func mergeDetails(dst, src map[string]string) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
@@ -0,0 +1,179 @@
+package source
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectAppServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		ancestry []model.Process
+		want     string
+		details  map[string]string
+	}{
+		{
+			name: "gunicorn worker",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd", Cmdline: "/sbin/init"},
+				{PID: 100, Command: "gunicorn", Cmdline: "/srv/venv/bin/python3 /srv/venv/bin/gunicorn -c /etc/gunicorn.py app:wsgi", App: "gunicorn"},
+				{PID: 101, Command: "gunicorn", Cmdline: "/srv/venv/bin/python3 /srv/venv/bin/gunicorn -c /etc/gunicorn.py app:wsgi", App: "gunicorn"},
+			},
+			want:    "gunicorn",
+			details: map[string]string{"role": "worker", "master": "100", "config": "/etc/gunicorn.py"},
+		},
+		{
+			name: "gunicorn config from env",
+			ancestry: []model.Process{
+				{PID: 100, Command: "gunicorn", Cmdline: "gunicorn: master [app]", Env: []string{"GUNICORN_CMD_ARGS=--workers 4 --config=/etc/g.py"}},
+			},
+			want:    "gunicorn",
+			details: map[string]string{"role": "master", "master": "100", "config": "/etc/g.py"},
+		},
+		{
+			name: "uwsgi worker",
+			ancestry: []model.Process{
+				{PID: 200, Command: "uwsgi", Cmdline: "uwsgi --ini /etc/uwsgi/app.ini"},
+				{PID: 201, Command: "uwsgi", Cmdline: "uWSGI worker 3"},
+			},
+			want:    "uwsgi",
+			details: map[string]string{"role": "worker", "master": "200", "worker": "3", "config": "/etc/uwsgi/app.ini"},
+		},
+		{
+			name: "celery pool worker",
+			ancestry: []model.Process{
+				{PID: 300, Command: "celery", Cmdline: "/usr/bin/python3 -m celery -A proj worker", App: "celery"},
+				{PID: 301, Command: "celery", Cmdline: "[celeryd: celery@host:ForkPoolWorker-2]"},
+			},
+			want:    "celery",
+			details: map[string]string{"role": "worker", "master": "300", "worker": "2", "app": "proj"},
+		},
+		{
+			name: "puma cluster worker",
+			ancestry: []model.Process{
+				{PID: 400, Command: "ruby", Cmdline: "puma 6.4.0 (tcp://0.0.0.0:3000) [api]"},
+				{PID: 401, Command: "ruby", Cmdline: "puma: cluster worker 1: 400 [api]"},
+			},
+			want:    "puma",
+			details: map[string]string{"role": "worker", "master": "400", "worker": "1"},
+		},
+		{
+			name: "php-fpm pool",
+			ancestry: []model.Process{
+				{PID: 500, Command: "php-fpm8.2", Cmdline: "php-fpm: master process (/etc/php/8.2/fpm/php-fpm.conf)"},
+				{PID: 501, Command: "php-fpm8.2", Cmdline: "php-fpm: pool www"},
+			},
+			want:    "php-fpm",
+			details: map[string]string{"role": "worker", "master": "500", "pool": "www", "config": "/etc/php/8.2/fpm/php-fpm.conf"},
+		},
+		{
+			name: "unicorn worker",
+			ancestry: []model.Process{
+				{PID: 600, Command: "ruby", Cmdline: "unicorn master -c /srv/app/unicorn.rb -E production"},
+				{PID: 601, Command: "ruby", Cmdline: "unicorn worker[0] -c /srv/app/unicorn.rb -E production"},
+			},
+			want:    "unicorn",
+			details: map[string]string{"role": "worker", "master": "600", "worker": "0", "config": "/srv/app/unicorn.rb"},
+		},
+		{
+			name: "foreman procfile process",
+			ancestry: []model.Process{
+				{PID: 700, Command: "ruby", Cmdline: "/usr/bin/ruby /usr/local/bin/foreman start", App: "foreman start", WorkingDir: "/srv/app"},
+				{PID: 701, Command: "sh", Cmdline: "sh -c bundle exec rails s"},
+				{PID: 702, Command: "ruby", Cmdline: "ruby bin/rails s", Env: []string{"PS=web.1"}},
+			},
+			want:    "foreman",
+			details: map[string]string{"role": "child", "master": "700", "process": "web.1", "procfile": "/srv/app/Procfile"},
+		},
+		{
+			name: "nodemon via node_modules",
+			ancestry: []model.Process{
+				{PID: 800, Command: "node", Cmdline: "node /usr/lib/node_modules/nodemon/bin/nodemon.js --config nm.json", App: "nodemon.js"},
+				{PID: 801, Command: "node", Cmdline: "node server.js", App: "server.js"},
+			},
+			want:    "nodemon",
+			details: map[string]string{"role": "child", "master": "800", "config": "nm.json"},
+		},
+		{
+			name: "nodemon bin script",
+			ancestry: []model.Process{
+				{PID: 800, Command: "node", Cmdline: "node /usr/local/bin/nodemon --config nm.json", App: "nodemon"},
+				{PID: 801, Command: "node", Cmdline: "node server.js", App: "server.js"},
+			},
+			want:    "nodemon",
+			details: map[string]string{"role": "child", "master": "800", "config": "nm.json"},
+		},
+		{
+			name: "plain process",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd", Cmdline: "/sbin/init"},
+				{PID: 900, Command: "godoc", Cmdline: "/home/godfrey/go/bin/godoc -http :6060"},
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := detectAppServer(tt.ancestry)
+			if tt.want == "" {
+				if src != nil {
+					t.Fatalf("detectAppServer() = %+v, want nil", src)
+				}
+				return
+			}
+			if src == nil {
+				t.Fatalf("detectAppServer() = nil, want %s", tt.want)
+			}
+			if src.Name != tt.want {
+				t.Errorf("Name = %q, want %q", src.Name, tt.want)
+			}
+			if !maps.Equal(src.Details, tt.details) {
+				t.Errorf("Details = %v, want %v", src.Details, tt.details)
+			}
+		})
+	}
+}
+
+func TestDetectSupervisorMatchesWholeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		ancestry []model.Process
+		want     string
+	}{
+		{
+			name:     "god substring",
+			ancestry: []model.Process{{PID: 10, Command: "godoc", Cmdline: "/home/godfrey/go/bin/godoc"}},
+			want:     "",
+		},
+		{
+			name:     "god via ruby",
+			ancestry: []model.Process{{PID: 10, Command: "ruby", Cmdline: "/usr/bin/ruby /usr/local/bin/god -c app.god", App: "god"}},
+			want:     "god",
+		},
+		{
+			name:     "supervisord via python",
+			ancestry: []model.Process{{PID: 10, Command: "python3", Cmdline: "/usr/bin/python3 /usr/bin/supervisord -n", App: "supervisord"}},
+			want:     "supervisord",
+		},
+		{
+			name:     "keyword in arguments",
+			ancestry: []model.Process{{PID: 10, Command: "grep", Cmdline: "grep -r monit /etc"}},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if src := detectSupervisor(tt.ancestry); src != nil {
+				got = src.Name
+			}
+			if got != tt.want {
+				t.Errorf("detectSupervisor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
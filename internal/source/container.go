@@ -1,16 +1,15 @@
 package source
 
 import (
-	"os"
-	"strconv"
 	"strings"
 
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func detectContainer(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
-		data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
+		data, err := procpkg.Snapshot().Cgroup(p.PID)
 		if err != nil {
 			continue
 		}
@@ -45,9 +44,26 @@ func detectContainer(ancestry []model.Process) *model.Source {
 			}
 		}
 	}
+
+	// Fall back to namespace isolation for runtimes whose cgroup paths we don't recognise
+	if len(ancestry) > 0 && containerNamespaces(ancestry[len(ancestry)-1]) {
+		return &model.Source{
+			Type: model.SourceContainer,
+			Name: "container",
+		}
+	}
 	return nil
 }
 
-func itoa(n int) string {
-	return strconv.Itoa(n)
+// containerNamespaces reports whether a process is isolated the way container
+// runtimes isolate it: its own mount, pid, network and uts namespaces. Sandboxes
+// such as browser renderers usually unshare only some of these.
+func containerNamespaces(p model.Process) bool {
+	isolated := map[string]bool{}
+	for _, ns := range p.Namespaces {
+		if ns.DiffersFromInit {
+			isolated[ns.Type] = true
+		}
+	}
+	return isolated["mnt"] && isolated["pid"] && isolated["net"] && isolated["uts"]
 }
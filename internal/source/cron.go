@@ -1,15 +1,306 @@
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// System crontabs have a user field after the schedule, per-user spool
+// files are named after the user and do not
+var (
+	systemCrontabs  = []string{"/etc/crontab"}
+	systemCronDirs  = []string{"/etc/cron.d"}
+	userCrontabDirs = []string{
+		"/var/spool/cron/crontabs", // Debian, Ubuntu
+		"/var/spool/cron",          // RHEL, Fedora
+		"/var/cron/tabs",           // FreeBSD
+		"/usr/lib/cron/tabs",       // macOS
+	}
+	anacrontab      = "/etc/anacrontab"
+	anacronSpoolDir = "/var/spool/anacron"
+)
+
+// cronEntry is one job line of a crontab or anacrontab
+type cronEntry struct {
+	file     string
+	line     int
+	schedule string
+	user     string
+	command  string
+	// anacron period in days and job identifier
+	period int
+	jobID  string
+}
 
 func detectCron(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
-		if p.Command == "cron" || p.Command == "crond" {
+		if p.Command == "cron" || p.Command == "crond" || p.Command == "anacron" {
 			return &model.Source{
-				Type: model.SourceCron,
-				Name: "cron",
+				Type:    model.SourceCron,
+				Name:    "cron",
+				Details: cronJobDetails(ancestry),
 			}
 		}
 	}
 	return nil
 }
+
+// cronJobDetails finds the crontab line that started the job: the first
+// process below the last cron/anacron ancestor, usually "/bin/sh -c <command>"
+func cronJobDetails(ancestry []model.Process) map[string]string {
+	idx := -1
+	for i, p := range ancestry {
+		if p.Command == "cron" || p.Command == "crond" || p.Command == "anacron" {
+			idx = i
+		}
+	}
+	if idx == -1 || idx+1 >= len(ancestry) {
+		return nil
+	}
+	job := ancestry[idx+1]
+
+	var entries []cronEntry
+	if ancestry[idx].Command == "anacron" {
+		entries = readAnacrontab(anacrontab)
+	} else {
+		entries = readCrontabs()
+	}
+	entry, ok := matchCronEntry(entries, job)
+	if !ok {
+		return nil
+	}
+
+	d := details(
+		"file", entry.file,
+		"line", strconv.Itoa(entry.line),
+		"user", entry.user,
+		"command", entry.command,
+	)
+	if entry.period > 0 {
+		d["schedule"] = "every " + strconv.Itoa(entry.period) + " day(s) (anacron)"
+		if last := anacronLastRun(entry.jobID); !last.IsZero() {
+			d["next_run"] = last.AddDate(0, 0, entry.period).Format("Mon 2006-01-02")
+		}
+	} else {
+		d["schedule"] = entry.schedule
+		if s, err := parseCronSchedule(entry.schedule); err == nil {
+			if next := s.next(time.Now()); !next.IsZero() {
+				d["next_run"] = next.Format("Mon 2006-01-02 15:04 -07:00")
+			}
+		}
+	}
+
+	if script := runPartsScript(ancestry[idx+1:]); script != "" {
+		d["script"] = script
+	}
+	return d
+}
+
+// runPartsScript names the script run-parts is running for jobs such as
+// cron.daily: an argument of a process below run-parts inside its directory
+func runPartsScript(procs []model.Process) string {
+	for i, p := range procs {
+		fields := strings.Fields(p.Cmdline)
+		if len(fields) < 2 || filepath.Base(fields[0]) != "run-parts" {
+			continue
+		}
+		dir := filepath.Clean(fields[len(fields)-1])
+		for _, q := range procs[i+1:] {
+			for _, arg := range strings.Fields(q.Cmdline) {
+				if filepath.Dir(arg) == dir {
+					return arg
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// readCrontabs reads the system crontab, /etc/cron.d and the user spool
+func readCrontabs() []cronEntry {
+	var entries []cronEntry
+	for _, f := range systemCrontabs {
+		entries = append(entries, readCrontab(f, "")...)
+	}
+	for _, dir := range systemCronDirs {
+		files, _ := os.ReadDir(dir)
+		for _, f := range files {
+			if !f.Type().IsRegular() || strings.HasPrefix(f.Name(), ".") {
+				continue
+			}
+			entries = append(entries, readCrontab(filepath.Join(dir, f.Name()), "")...)
+		}
+	}
+	for _, dir := range userCrontabDirs {
+		files, _ := os.ReadDir(dir)
+		for _, f := range files {
+			if !f.Type().IsRegular() || strings.HasPrefix(f.Name(), ".") {
+				continue
+			}
+			entries = append(entries, readCrontab(filepath.Join(dir, f.Name()), f.Name())...)
+		}
+	}
+	return entries
+}
+
+func readCrontab(file, user string) []cronEntry {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	return parseCrontab(string(data), file, user)
+}
+
+// parseCrontab parses crontab job lines. user is the owner of a per-user
+// crontab; when empty the file is a system crontab with a user column.
+func parseCrontab(content, file, user string) []cronEntry {
+	var entries []cronEntry
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' || isEnvAssignment(line) {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		n := 5
+		if strings.HasPrefix(line, "@") {
+			n = 1
+		}
+		if len(fields) <= n {
+			continue
+		}
+		schedule := strings.Join(fields[:n], " ")
+		rest := fields[n:]
+
+		entryUser := user
+		if user == "" {
+			if len(rest) < 2 {
+				continue
+			}
+			entryUser, rest = rest[0], rest[1:]
+		}
+
+		entries = append(entries, cronEntry{
+			file:     file,
+			line:     i + 1,
+			schedule: schedule,
+			user:     entryUser,
+			command:  cronCommand(strings.Join(rest, " ")),
+		})
+	}
+	return entries
+}
+
+// cronCommand cuts the command at the first unescaped %, after which cron
+// feeds the text to stdin
+func cronCommand(cmd string) string {
+	for i := 0; i < len(cmd); i++ {
+		if cmd[i] == '\\' {
+			i++
+			continue
+		}
+		if cmd[i] == '%' {
+			cmd = cmd[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.ReplaceAll(cmd, `\%`, "%"))
+}
+
+// isEnvAssignment reports whether a crontab line sets a variable (MAILTO=root)
+func isEnvAssignment(line string) bool {
+	name, _, ok := strings.Cut(line, "=")
+	return ok && !strings.ContainsAny(strings.TrimSpace(name), " \t*@")
+}
+
+// readAnacrontab parses "period delay job-identifier command" lines
+func readAnacrontab(file string) []cronEntry {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var entries []cronEntry
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' || isEnvAssignment(line) {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		period, err := strconv.Atoi(fields[0])
+		switch fields[0] {
+		case "@daily":
+			period, err = 1, nil
+		case "@weekly":
+			period, err = 7, nil
+		case "@monthly":
+			period, err = 30, nil
+		}
+		if err != nil {
+			continue
+		}
+		entries = append(entries, cronEntry{
+			file:    file,
+			line:    i + 1,
+			user:    "root",
+			command: strings.Join(fields[3:], " "),
+			period:  period,
+			jobID:   fields[2],
+		})
+	}
+	return entries
+}
+
+// anacronLastRun reads the date anacron last ran a job (YYYYMMDD)
+func anacronLastRun(jobID string) time.Time {
+	data, err := os.ReadFile(filepath.Join(anacronSpoolDir, jobID))
+	if err != nil {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation("20060102", strings.TrimSpace(string(data)), time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// matchCronEntry finds the entry that started job. cron runs the command
+// through "/bin/sh -c", so the shell's argument is compared first; a shell
+// that exec'd the command leaves only its arguments, compared without quotes.
+func matchCronEntry(entries []cronEntry, job model.Process) (cronEntry, bool) {
+	cmdline := normalizeCommand(job.Cmdline)
+	if fields := strings.Fields(job.Cmdline); len(fields) > 2 && shells[filepath.Base(fields[0])] && fields[1] == "-c" {
+		cmdline = normalizeCommand(strings.Join(fields[2:], " "))
+	}
+
+	userMatches := func(e cronEntry) bool {
+		return job.User == "" || e.user == job.User
+	}
+	for _, e := range entries {
+		if userMatches(e) && normalizeCommand(e.command) == cmdline {
+			return e, true
+		}
+	}
+	// Fall back to the program the entry runs appearing in the job's command line
+	jobArgs := strings.Fields(cmdline)
+	for _, e := range entries {
+		prog := strings.Fields(normalizeCommand(e.command))
+		if len(prog) > 0 && userMatches(e) && strings.Contains(prog[0], "/") && slices.Contains(jobArgs, prog[0]) {
+			return e, true
+		}
+	}
+	return cronEntry{}, false
+}
+
+func normalizeCommand(cmd string) string {
+	cmd = strings.NewReplacer(`"`, "", `'`, "").Replace(cmd)
+	return strings.Join(strings.Fields(cmd), " ")
+}
@@ -0,0 +1,108 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const testSystemCrontab = `SHELL=/bin/sh
+PATH=/usr/local/sbin:/usr/local/bin:/sbin:/bin:/usr/sbin:/usr/bin
+
+# m h dom mon dow user	command
+17 *	* * *	root	cd / && run-parts --report /etc/cron.hourly
+25 6	* * *	root	test -x /usr/sbin/anacron || ( cd / && run-parts --report /etc/cron.daily )
+0 3 * * * backup /opt/backup/run.sh --full >> /var/log/backup.log 2>&1
+@reboot root /usr/local/bin/warmup
+`
+
+func TestParseCrontab(t *testing.T) {
+	entries := parseCrontab(testSystemCrontab, "/etc/crontab", "")
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+	want := cronEntry{file: "/etc/crontab", line: 7, schedule: "0 3 * * *", user: "backup", command: "/opt/backup/run.sh --full >> /var/log/backup.log 2>&1"}
+	if entries[2] != want {
+		t.Errorf("entry = %+v, want %+v", entries[2], want)
+	}
+	if entries[3].schedule != "@reboot" || entries[3].command != "/usr/local/bin/warmup" {
+		t.Errorf("@reboot entry = %+v", entries[3])
+	}
+
+	user := parseCrontab(`MAILTO=""
+*/5 * * * * /home/alice/bin/sync 'a b' % stdin text
+`, "/var/spool/cron/crontabs/alice", "alice")
+	if len(user) != 1 || user[0].user != "alice" || user[0].command != "/home/alice/bin/sync 'a b'" {
+		t.Errorf("user crontab = %+v", user)
+	}
+}
+
+func TestMatchCronEntry(t *testing.T) {
+	entries := append(parseCrontab(testSystemCrontab, "/etc/crontab", ""),
+		parseCrontab("0 * * * * python3 /home/alice/report.py --to 'ops team'\n", "/var/spool/cron/crontabs/alice", "alice")...)
+
+	tests := []struct {
+		name     string
+		job      model.Process
+		wantLine int
+		wantFile string
+	}{
+		{
+			name:     "shell running the command",
+			job:      model.Process{User: "backup", Cmdline: "/bin/sh -c /opt/backup/run.sh --full >> /var/log/backup.log 2>&1"},
+			wantLine: 7,
+			wantFile: "/etc/crontab",
+		},
+		{
+			name:     "command exec'd by the shell",
+			job:      model.Process{User: "alice", Cmdline: "python3 /home/alice/report.py --to ops team"},
+			wantLine: 1,
+			wantFile: "/var/spool/cron/crontabs/alice",
+		},
+		{
+			name:     "script run through an interpreter",
+			job:      model.Process{User: "backup", Cmdline: "/bin/bash /opt/backup/run.sh --full"},
+			wantLine: 7,
+			wantFile: "/etc/crontab",
+		},
+		{
+			name:     "run-parts",
+			job:      model.Process{User: "root", Cmdline: "/bin/sh -c cd / && run-parts --report /etc/cron.hourly"},
+			wantLine: 5,
+			wantFile: "/etc/crontab",
+		},
+		{
+			name: "other user",
+			job:  model.Process{User: "root", Cmdline: "/bin/sh -c /opt/backup/run.sh --full >> /var/log/backup.log 2>&1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := matchCronEntry(entries, tt.job)
+			if tt.wantFile == "" {
+				if ok {
+					t.Fatalf("matched %+v, want no match", e)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("no match")
+			}
+			if e.file != tt.wantFile || e.line != tt.wantLine {
+				t.Errorf("matched %s:%d, want %s:%d", e.file, e.line, tt.wantFile, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestRunPartsScript(t *testing.T) {
+	procs := []model.Process{
+		{Cmdline: "/bin/sh -c cd / && run-parts --report /etc/cron.daily"},
+		{Cmdline: "run-parts --report /etc/cron.daily"},
+		{Cmdline: "/bin/sh /etc/cron.daily/logrotate"},
+	}
+	if got := runPartsScript(procs); got != "/etc/cron.daily/logrotate" {
+		t.Errorf("runPartsScript() = %q, want /etc/cron.daily/logrotate", got)
+	}
+}
@@ -0,0 +1,163 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed five-field cron expression. Each field is a
+// bitmask of the values it allows.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// cron matches either day field when both are restricted
+	domStar, dowStar bool
+	reboot           bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+var cronDayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseCronSchedule parses "m h dom mon dow" or one of the @ macros
+func parseCronSchedule(expr string) (cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "@reboot" {
+		return cronSchedule{reboot: true}, nil
+	}
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return s, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return s, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return s, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return s, fmt.Errorf("month: %w", err)
+	}
+	// 7 is also Sunday
+	if s.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return s, fmt.Errorf("day of week: %w", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField parses a comma separated list of "*", "n", "a-b" with an
+// optional "/step". names are matched case-insensitively from min.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			a, b, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(a, min, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = cronValue(b, min, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+func cronValue(s string, min int, names []string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid value %q", s)
+}
+
+func (s cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first time after from the schedule fires, or the zero
+// time for @reboot and schedules that never match (Feb 30)
+func (s cronSchedule) next(from time.Time) time.Time {
+	if s.reboot {
+		return time.Time{}
+	}
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
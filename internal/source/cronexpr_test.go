@@ -0,0 +1,60 @@
+package source
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, time.January, 14, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 14, 10, 31, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 1, 15, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 14, 10, 45, 0, 0, time.UTC)},
+		{"5-10/5 10 * * *", time.Date(2026, 1, 15, 10, 5, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 6 * * mon-fri", time.Date(2026, 1, 15, 6, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * FEB *", time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted: the 20th or a Friday
+		{"0 0 20 * 5", time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@reboot", time.Time{}},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCronSchedule(%q): %v", tt.expr, err)
+			}
+			if got := s.next(from); !got.Equal(tt.want) {
+				t.Errorf("next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+	} {
+		if _, err := parseCronSchedule(expr); err == nil {
+			t.Errorf("parseCronSchedule(%q) succeeded, want error", expr)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package source
 
 import (
+	"fmt"
+	"math"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,6 +13,8 @@ import (
 )
 
 type envSuspiciousRule struct {
+	id          string
+	severity    model.Severity
 	pattern     string
 	match       func(key, pattern string) bool
 	warning     string
@@ -18,12 +24,16 @@ type envSuspiciousRule struct {
 var (
 	envVarRules = []envSuspiciousRule{
 		{
-			pattern: "LD_PRELOAD",
-			match:   func(key, pattern string) bool { return key == pattern },
-			warning: "Process sets LD_PRELOAD (potential library injection)",
+			id:       "env-ld-preload",
+			severity: model.SeverityCritical,
+			pattern:  "LD_PRELOAD",
+			match:    func(key, pattern string) bool { return key == pattern },
+			warning:  "Process sets LD_PRELOAD (potential library injection)",
 		},
 
 		{
+			id:          "env-dyld",
+			severity:    model.SeverityCritical,
 			pattern:     "DYLD_",
 			match:       strings.HasPrefix,
 			warning:     "Process sets DYLD_* variables (potential library injection)",
@@ -33,11 +43,39 @@ var (
 )
 
 func Detect(ancestry []model.Process) model.Source {
+	src := detectPrimary(ancestry)
+
+	// App servers (gunicorn, puma, foreman, ...) run under whatever started
+	// them. They become the source when nothing more specific than a shell or
+	// init was found, otherwise their details are added to the primary source.
+	appSrv := detectAppServer(ancestry)
+	if appSrv == nil {
+		return src
+	}
+	switch {
+	case src.Type == model.SourceShell, src.Type == model.SourceInit, src.Type == model.SourceUnknown,
+		src.Type == model.SourceSupervisor && src.Name == "init":
+		return *appSrv
+	}
+	if src.Details == nil {
+		src.Details = make(map[string]string)
+	}
+	src.Details["appserver"] = appSrv.Name
+	mergeDetails(src.Details, appSrv.Details)
+	return src
+}
+
+func detectPrimary(ancestry []model.Process) model.Source {
 	// Detection order prioritizes platform-specific init systems
 	// over generic supervisor detection to avoid false positives
 	if src := detectContainer(ancestry); src != nil {
 		return *src
 	}
+	// A job matched to its crontab line is more specific than the init
+	// system the cron daemon runs under
+	if src := detectCron(ancestry); src != nil && len(src.Details) > 0 {
+		return *src
+	}
 	if src := detectSystemd(ancestry); src != nil {
 		return *src
 	}
@@ -69,9 +107,10 @@ func Detect(ancestry []model.Process) model.Source {
 }
 
 // env suspicious warnings returns warnings for known env based library injection patterns
-func envSuspiciousWarnings(env []string) []string {
+func envSuspiciousWarnings(env []string) []model.Warning {
 	matched := make([]bool, len(envVarRules))
 	matchedKeys := make([]map[string]struct{}, len(envVarRules))
+	evidence := make([]string, len(envVarRules))
 
 	// init per rule key capture only for rules that include keys
 	for i, rule := range envVarRules {
@@ -95,11 +134,13 @@ func envSuspiciousWarnings(env []string) []string {
 			matched[i] = true
 			if rule.includeKeys {
 				matchedKeys[i][key] = struct{}{}
+			} else {
+				evidence[i] = entry
 			}
 		}
 	}
 
-	var warnings []string
+	var warnings []model.Warning
 
 	// emit warnings in the same order as envVarRules
 	for i, rule := range envVarRules {
@@ -107,7 +148,12 @@ func envSuspiciousWarnings(env []string) []string {
 			continue
 		}
 		if !rule.includeKeys {
-			warnings = append(warnings, rule.warning)
+			warnings = append(warnings, model.Warning{
+				ID:       rule.id,
+				Severity: rule.severity,
+				Message:  rule.warning,
+				Evidence: evidence[i],
+			})
 			continue
 		}
 
@@ -117,73 +163,165 @@ func envSuspiciousWarnings(env []string) []string {
 			keys = append(keys, key)
 		}
 		sort.Strings(keys)
-		warnings = append(warnings, rule.warning+": "+strings.Join(keys, ", "))
+		warnings = append(warnings, model.Warning{
+			ID:       rule.id,
+			Severity: rule.severity,
+			Message:  rule.warning + ": " + strings.Join(keys, ", "),
+			Evidence: strings.Join(keys, ","),
+		})
 	}
 
 	return warnings
 }
 
-func Warnings(p []model.Process) []string {
-	var w []string
+func Warnings(p []model.Process) []model.Warning {
+	var w []model.Warning
 
 	last := p[len(p)-1]
 
-	// Restart count detection (count consecutive same-command entries)
-	restartCount := 0
-	lastCmd := ""
-	for _, proc := range p {
-		if proc.Command == lastCmd {
-			restartCount++
-		}
-		lastCmd = proc.Command
-	}
-	if restartCount > 5 {
-		w = append(w, "Process or ancestor restarted more than 5 times")
+	if nested := NestedSameName(p); nested > 5 {
+		w = append(w, model.Warning{
+			ID:       "nested-same-name",
+			Severity: model.SeverityInfo,
+			Message:  "Process has more than 5 nested same-name ancestors",
+			Evidence: strconv.Itoa(nested) + " nested same-name ancestors",
+		})
 	}
 
 	// Health warnings
 	switch last.Health {
 	case "zombie":
-		w = append(w, "Process is a zombie (defunct)")
+		w = append(w, model.Warning{ID: "zombie", Severity: model.SeverityWarn, Message: "Process is a zombie (defunct)"})
 	case "stopped":
-		w = append(w, "Process is stopped (T state)")
+		w = append(w, model.Warning{ID: "stopped", Severity: model.SeverityWarn, Message: "Process is stopped (T state)"})
 	case "high-cpu":
-		w = append(w, "Process is using high CPU (>2h total)")
+		w = append(w, model.Warning{ID: "high-cpu", Severity: model.SeverityWarn, Message: "Process is using high CPU (>80% of a CPU, or of its cgroup CPU quota, on average since it started)"})
 	case "high-mem":
-		w = append(w, "Process is using high memory (>1GB RSS)")
+		msg := "Process is using high memory (>1GB RSS)"
+		if mem := last.MemoryCgroup; mem != nil && mem.MemoryMax > 0 {
+			msg = "Process is using high memory (>80% of its cgroup's memory.max=" + shortSize(mem.MemoryMax) + ")"
+		}
+		w = append(w, model.Warning{ID: "high-mem", Severity: model.SeverityWarn, Message: msg})
+	}
+
+	if ww, ok := oomWarning(last); ok {
+		w = append(w, ww)
+	}
+
+	// Binary or libraries replaced on disk (e.g. by a package upgrade) but still in use
+	if last.ExeDeleted {
+		msg := "Executable was deleted or replaced on disk; restart to run the current binary"
+		if owner := last.DeletedOwners[last.Exe]; owner != "" {
+			msg += " (now from " + owner + ")"
+		}
+		w = append(w, model.Warning{
+			ID:       "deleted-exe",
+			Severity: model.SeverityWarn,
+			Message:  msg,
+			Evidence: last.Exe,
+		})
+	}
+	if len(last.DeletedLibs) > 0 {
+		msg := fmt.Sprintf("Process still uses %d deleted shared librar%s; restart to load the upgraded versions", len(last.DeletedLibs), pluralSuffix(len(last.DeletedLibs), "y", "ies"))
+		if pkgs := libOwners(last.DeletedLibs, last.DeletedOwners); len(pkgs) > 0 {
+			msg += " (from " + strings.Join(pkgs, ", ") + ")"
+		}
+		w = append(w, model.Warning{
+			ID:       "deleted-libs",
+			Severity: model.SeverityWarn,
+			Message:  msg,
+			Evidence: strings.Join(last.DeletedLibs, ","),
+		})
+	}
+
+	// Binary integrity against the package database
+	if bin := last.Binary; bin != nil && !last.ExeDeleted {
+		switch {
+		case bin.Verification == model.BinaryModified:
+			w = append(w, model.Warning{
+				ID:       "binary-modified",
+				Severity: model.SeverityCritical,
+				Message:  fmt.Sprintf("Executable does not match the checksum recorded by its package (%s %s)", bin.Package, bin.PackageVersion),
+				Evidence: "sha256 " + bin.SHA256,
+			})
+		case bin.PackageDBAvailable && bin.Package == "":
+			w = append(w, model.Warning{
+				ID:       "binary-unowned",
+				Severity: model.SeverityInfo,
+				Message:  "Executable is not owned by any installed package",
+				Evidence: bin.Path,
+			})
+		}
 	}
 
 	if IsPublicBind(last.BindAddresses) {
-		w = append(w, "Process is listening on a public interface")
+		w = append(w, model.Warning{
+			ID:       "public-bind",
+			Severity: model.SeverityWarn,
+			Message:  "Process is listening on a public interface",
+			Evidence: strings.Join(last.BindAddresses, ","),
+		})
+	}
+
+	// Listening inside another network namespace (e.g. a container)
+	if len(last.ListeningPorts) > 0 {
+		for _, ns := range last.Namespaces {
+			if ns.Type == "net" && ns.DiffersFromInit {
+				w = append(w, model.Warning{
+					ID:       "netns-isolated",
+					Severity: model.SeverityInfo,
+					Message:  "Process listens inside a separate network namespace; its ports are only reachable from the host if published or forwarded",
+					Evidence: "netns " + strconv.FormatUint(ns.Inode, 10),
+				})
+			}
+		}
 	}
 
 	if last.User == "root" {
-		w = append(w, "Process is running as root")
+		w = append(w, rootWarning(last.Security))
 	}
 
+	// Capabilities and confinement (Linux)
+	w = append(w, securityWarnings(last)...)
+
 	if Detect(p).Type == model.SourceUnknown {
-		w = append(w, "No known supervisor or service manager detected")
+		w = append(w, model.Warning{ID: "no-supervisor", Severity: model.SeverityInfo, Message: "No known supervisor or service manager detected"})
 	}
 
 	// Warn if process is very old (>90 days)
 	if time.Since(last.StartedAt).Hours() > 90*24 {
-		w = append(w, "Process has been running for over 90 days")
+		w = append(w, model.Warning{
+			ID:       "long-running",
+			Severity: model.SeverityInfo,
+			Message:  "Process has been running for over 90 days",
+			Evidence: "started " + last.StartedAt.Format(time.RFC3339),
+		})
 	}
 
 	// Warn if working dir is suspicious
 	suspiciousDirs := map[string]bool{"/": true, "/tmp": true, "/var/tmp": true}
 	if suspiciousDirs[last.WorkingDir] {
-		w = append(w, "Process is running from a suspicious working directory: "+last.WorkingDir)
+		w = append(w, model.Warning{
+			ID:       "suspicious-cwd",
+			Severity: model.SeverityWarn,
+			Message:  "Process is running from a suspicious working directory: " + last.WorkingDir,
+			Evidence: last.WorkingDir,
+		})
 	}
 
 	// Warn if container and no healthcheck (placeholder, as healthcheck not detected)
 	if last.Container != "" {
-		w = append(w, "No healthcheck detected for container (best effort)")
+		w = append(w, model.Warning{ID: "container-no-healthcheck", Severity: model.SeverityInfo, Message: "No healthcheck detected for container (best effort)"})
 	}
 
 	// Warn if service name and process name mismatch
 	if last.Service != "" && last.Command != "" && last.Service != last.Command {
-		w = append(w, "Service name and process name do not match")
+		w = append(w, model.Warning{
+			ID:       "service-name-mismatch",
+			Severity: model.SeverityInfo,
+			Message:  "Service name and process name do not match",
+			Evidence: last.Service + " != " + last.Command,
+		})
 	}
 
 	// Include warnings based on suspicious env variables
@@ -191,3 +329,162 @@ func Warnings(p []model.Process) []string {
 
 	return w
 }
+
+// oomWarning reports OOM kills in the target's cgroup, counted by the
+// kernel for the cgroup's current lifetime and found in the kernel log for
+// earlier ones, e.g. "This service's cgroup has had 3 OOM kills,
+// memory.max=512M, current usage 480M"
+func oomWarning(p model.Process) (model.Warning, bool) {
+	mem := p.MemoryCgroup
+	if mem == nil {
+		return model.Warning{}, false
+	}
+	kills := max(int(mem.OOMKills), len(mem.Kills))
+	if kills == 0 {
+		return sameCommandOOMWarning(p)
+	}
+
+	owner := "process"
+	switch {
+	case p.Container != "":
+		owner = "container"
+	case strings.HasSuffix(UnitFromCgroup(mem.Path), ".service"):
+		owner = "service"
+	}
+	limit := "max"
+	if mem.MemoryMax > 0 {
+		limit = shortSize(mem.MemoryMax)
+	}
+
+	evidence := []string{"cgroup " + mem.Path}
+	if mem.OOMKills > 0 || mem.OOMEvents > 0 {
+		evidence = append(evidence, fmt.Sprintf("memory.events oom=%d oom_kill=%d", mem.OOMEvents, mem.OOMKills))
+	}
+	if n := len(mem.Kills); n > 0 {
+		k := mem.Kills[n-1]
+		last := fmt.Sprintf("last logged kill: %s (pid %d)", k.Command, k.PID)
+		if !k.Time.IsZero() {
+			last += " at " + k.Time.Format("2006-01-02 15:04:05")
+		}
+		evidence = append(evidence, last)
+	}
+	if n := len(mem.SameCommandKills); n > 0 {
+		evidence = append(evidence, fmt.Sprintf("%d more of %s logged without a cgroup, same command elsewhere", n, p.Command))
+	}
+
+	return model.Warning{
+		ID:       "oom-kills",
+		Severity: model.SeverityWarn,
+		Message: fmt.Sprintf("This %s's cgroup has had %d OOM kill%s, memory.max=%s, current usage %s",
+			owner, kills, pluralSuffix(kills, "", "s"), limit, shortSize(mem.MemoryCurrent)),
+		Evidence: strings.Join(evidence, "; "),
+	}, true
+}
+
+// sameCommandOOMWarning notes OOM kills of processes running the same
+// command that the kernel logged without a cgroup. They may have been
+// anywhere on the host, so they are not held against this process's cgroup.
+func sameCommandOOMWarning(p model.Process) (model.Warning, bool) {
+	kills := p.MemoryCgroup.SameCommandKills
+	if len(kills) == 0 {
+		return model.Warning{}, false
+	}
+	k := kills[len(kills)-1]
+	last := fmt.Sprintf("last logged kill: pid %d", k.PID)
+	if !k.Time.IsZero() {
+		last += " at " + k.Time.Format("2006-01-02 15:04:05")
+	}
+	return model.Warning{
+		ID:       "oom-kills-same-command",
+		Severity: model.SeverityInfo,
+		Message: fmt.Sprintf("Kernel log has %d OOM kill%s of %s with no cgroup recorded (same command elsewhere, not counted against this cgroup)",
+			len(kills), pluralSuffix(len(kills), "", "s"), p.Command),
+		Evidence: last,
+	}, true
+}
+
+// shortSize formats bytes the way cgroup limits are usually written: 512M, 1.5G
+func shortSize(b uint64) string {
+	units := []string{"B", "K", "M", "G", "T"}
+	v, i := float64(b), 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	if v >= 100 || v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f%s", v, units[i])
+	}
+	return fmt.Sprintf("%.1f%s", v, units[i])
+}
+
+func pluralSuffix(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// NestedSameName counts ancestry entries running the same command as their
+// parent. This used to be reported as a restart count, but a supervisor
+// restarting a process does not nest it; a script re-running itself or a
+// pre-forking server does.
+func NestedSameName(p []model.Process) int {
+	nested := 0
+	for i := 1; i < len(p); i++ {
+		if p[i].Command == p[i-1].Command {
+			nested++
+		}
+	}
+	return nested
+}
+
+// RestartWarnings flags a process its supervisor keeps restarting
+func RestartWarnings(count int, from string) []model.Warning {
+	if count <= 5 {
+		return nil
+	}
+	return []model.Warning{{
+		ID:       "restart-loop",
+		Severity: model.SeverityWarn,
+		Message:  "Process was restarted more than 5 times by its supervisor",
+		Evidence: fmt.Sprintf("%d restarts (%s)", count, from),
+	}}
+}
+
+// libOwners returns the distinct packages now owning the deleted libraries
+func libOwners(libs []string, owners map[string]string) []string {
+	var pkgs []string
+	for _, lib := range libs {
+		if owner := owners[lib]; owner != "" && !slices.Contains(pkgs, owner) {
+			pkgs = append(pkgs, owner)
+		}
+	}
+	return pkgs
+}
+
+// FileWarnings returns warnings derived from the file context
+func FileWarnings(fc *model.FileContext) []model.Warning {
+	if fc == nil || fc.FileLimit <= 0 {
+		return nil
+	}
+	if usage := float64(fc.OpenFiles) / float64(fc.FileLimit) * 100; usage >= 80 {
+		return []model.Warning{{
+			ID:       "fd-limit",
+			Severity: model.SeverityWarn,
+			Message:  fmt.Sprintf("Process is approaching its open file limit (%d of %d)", fc.OpenFiles, fc.FileLimit),
+			Evidence: fmt.Sprintf("%.0f%%", usage),
+		}}
+	}
+	return nil
+}
+
+// FilterWarnings returns the warnings at or above the given severity
+func FilterWarnings(warnings []model.Warning, min model.Severity) []model.Warning {
+	var out []model.Warning
+	for _, w := range warnings {
+		if w.Severity.Rank() >= min.Rank() {
+			out = append(out, w)
+		}
+	}
+	return out
+}
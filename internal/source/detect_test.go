@@ -9,6 +9,14 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+func warningMessages(warnings []model.Warning) []string {
+	var out []string
+	for _, w := range warnings {
+		out = append(out, w.Message)
+	}
+	return out
+}
+
 func TestWarningsDetectsLDPreload(t *testing.T) {
 	p := []model.Process{
 		{PID: 999999, Command: "pm2", Cmdline: "pm2"},
@@ -22,7 +30,7 @@ func TestWarningsDetectsLDPreload(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p))
 	if !slices.Contains(warnings, "Process sets LD_PRELOAD (potential library injection)") {
 		t.Fatalf("expected LD_PRELOAD warning, got: %v", warnings)
 	}
@@ -44,7 +52,7 @@ func TestWarningsDetectsDYLDVars(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p))
 	want := "Process sets DYLD_* variables (potential library injection): DYLD_INSERT_LIBRARIES, DYLD_LIBRARY_PATH"
 	if !slices.Contains(warnings, want) {
 		t.Fatalf("expected DYLD warning %q, got: %v", want, warnings)
@@ -67,7 +75,7 @@ func TestWarningsIgnoresEmptyPreloadVars(t *testing.T) {
 		},
 	}
 
-	warnings := Warnings(p)
+	warnings := warningMessages(Warnings(p))
 	if slices.Contains(warnings, "Process sets LD_PRELOAD (potential library injection)") {
 		t.Fatalf("did not expect LD_PRELOAD warning, got: %v", warnings)
 	}
@@ -94,8 +102,8 @@ func FuzzEnvSuspiciousWarningsDeterministic(f *testing.F) {
 			}
 		}
 
-		w1 := envSuspiciousWarnings(parts)
-		w2 := envSuspiciousWarnings(parts)
+		w1 := warningMessages(envSuspiciousWarnings(parts))
+		w2 := warningMessages(envSuspiciousWarnings(parts))
 		if !slices.Equal(w1, w2) {
 			t.Fatalf("expected deterministic output, got %v vs %v", w1, w2)
 		}
@@ -143,7 +151,7 @@ func TestEnvSuspiciousWarnings(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := envSuspiciousWarnings(tt.env)
+			got := warningMessages(envSuspiciousWarnings(tt.env))
 			if !slices.Equal(got, tt.want) {
 				t.Fatalf("got %v, want %v", got, tt.want)
 			}
@@ -176,3 +184,216 @@ func FuzzWarningsNoPanic(f *testing.F) {
 		_ = Warnings(p)
 	})
 }
+
+func TestFilterWarnings(t *testing.T) {
+	warnings := []model.Warning{
+		{ID: "root-user", Severity: model.SeverityInfo, Message: "info"},
+		{ID: "public-bind", Severity: model.SeverityWarn, Message: "warn"},
+		{ID: "env-ld-preload", Severity: model.SeverityCritical, Message: "critical"},
+	}
+
+	tests := []struct {
+		min  model.Severity
+		want []string
+	}{
+		{min: model.SeverityInfo, want: []string{"info", "warn", "critical"}},
+		{min: model.SeverityWarn, want: []string{"warn", "critical"}},
+		{min: model.SeverityCritical, want: []string{"critical"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.min), func(t *testing.T) {
+			got := warningMessages(FilterWarnings(warnings, tt.min))
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarningsSecurityPosture(t *testing.T) {
+	base := model.Process{
+		PID:           123,
+		Command:       "app",
+		StartedAt:     time.Now(),
+		WorkingDir:    "/srv/app",
+		BindAddresses: []string{"0.0.0.0"},
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		sec     *model.SecurityContext
+		wantIDs []string
+		wantSev model.Severity
+	}{
+		{
+			name:    "unconfined root with full caps",
+			user:    "root",
+			sec:     &model.SecurityContext{UID: 0, CapEff: []string{"CAP_SYS_ADMIN"}, Seccomp: "disabled"},
+			wantIDs: []string{"root-user", "unconfined-public"},
+			wantSev: model.SeverityWarn,
+		},
+		{
+			name:    "confined root",
+			user:    "root",
+			sec:     &model.SecurityContext{UID: 0, CapEff: []string{"CAP_SYS_ADMIN"}, Seccomp: "filter", LSMLabel: "docker-default (enforce)"},
+			wantIDs: []string{"root-user"},
+			wantSev: model.SeverityInfo,
+		},
+		{
+			name:    "non-root with sys admin",
+			user:    "app",
+			sec:     &model.SecurityContext{UID: 1000, CapEff: []string{"CAP_SYS_ADMIN"}, Seccomp: "filter"},
+			wantIDs: []string{"nonroot-privileged-caps"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := base
+			p.User = tt.user
+			p.Security = tt.sec
+			warnings := Warnings([]model.Process{{PID: 1, Command: "systemd"}, p})
+
+			byID := map[string]model.Warning{}
+			for _, w := range warnings {
+				byID[w.ID] = w
+			}
+			for _, id := range tt.wantIDs {
+				if _, ok := byID[id]; !ok {
+					t.Fatalf("expected %s warning, got %v", id, warnings)
+				}
+			}
+			if tt.wantSev != "" && byID["root-user"].Severity != tt.wantSev {
+				t.Fatalf("root-user severity = %s, want %s", byID["root-user"].Severity, tt.wantSev)
+			}
+		})
+	}
+}
+
+func TestWarningsOOMKills(t *testing.T) {
+	const mb = 1 << 20
+	proc := model.Process{
+		PID:       123,
+		Command:   "java",
+		StartedAt: time.Now(),
+		Health:    "high-mem",
+		MemoryCgroup: &model.CgroupMemory{
+			Path:          "/system.slice/app.service",
+			MemoryMax:     512 * mb,
+			MemoryCurrent: 480 * mb,
+			OOMEvents:     3,
+			OOMKills:      3,
+			Kills:         []model.OOMKill{{PID: 99, Command: "java", TaskCgroup: "/system.slice/app.service"}},
+		},
+	}
+
+	msgs := warningMessages(Warnings([]model.Process{proc}))
+	for _, want := range []string{
+		"This service's cgroup has had 3 OOM kills, memory.max=512M, current usage 480M",
+		"Process is using high memory (>80% of its cgroup's memory.max=512M)",
+	} {
+		if !slices.Contains(msgs, want) {
+			t.Errorf("warnings %q do not contain %q", msgs, want)
+		}
+	}
+
+	proc.MemoryCgroup = &model.CgroupMemory{Path: "/user.slice", MemoryCurrent: 1536 * mb}
+	proc.Health = "healthy"
+	for _, msg := range warningMessages(Warnings([]model.Process{proc})) {
+		if strings.Contains(msg, "OOM") {
+			t.Errorf("unexpected OOM warning %q", msg)
+		}
+	}
+
+	// A java killed elsewhere on the host is not this cgroup's
+	proc.MemoryCgroup.SameCommandKills = []model.OOMKill{{PID: 7, Command: "java"}}
+	msgs = warningMessages(Warnings([]model.Process{proc}))
+	want := "Kernel log has 1 OOM kill of java with no cgroup recorded (same command elsewhere, not counted against this cgroup)"
+	if !slices.Contains(msgs, want) {
+		t.Errorf("warnings %q do not contain %q", msgs, want)
+	}
+	for _, msg := range msgs {
+		if strings.Contains(msg, "cgroup has had") {
+			t.Errorf("same-command kill counted against the cgroup: %q", msg)
+		}
+	}
+}
+
+func TestWarningsDeletedFilesNameOwners(t *testing.T) {
+	p := []model.Process{{
+		PID:         123,
+		Command:     "nginx",
+		StartedAt:   time.Now(),
+		User:        "www-data",
+		Exe:         "/usr/sbin/nginx",
+		ExeDeleted:  true,
+		DeletedLibs: []string{"/usr/lib/x86_64-linux-gnu/libcrypto.so.3", "/usr/lib/x86_64-linux-gnu/libssl.so.3", "/opt/vendor/libfoo.so"},
+		DeletedOwners: map[string]string{
+			"/usr/sbin/nginx":                          "nginx-core 1.24.0-2",
+			"/usr/lib/x86_64-linux-gnu/libcrypto.so.3": "libssl3 3.0.13-0ubuntu3",
+			"/usr/lib/x86_64-linux-gnu/libssl.so.3":    "libssl3 3.0.13-0ubuntu3",
+		},
+	}}
+
+	warnings := warningMessages(Warnings(p))
+	for _, want := range []string{
+		"Executable was deleted or replaced on disk; restart to run the current binary (now from nginx-core 1.24.0-2)",
+		"Process still uses 3 deleted shared libraries; restart to load the upgraded versions (from libssl3 3.0.13-0ubuntu3)",
+	} {
+		if !slices.Contains(warnings, want) {
+			t.Errorf("expected %q, got: %v", want, warnings)
+		}
+	}
+}
+
+func TestShortSize(t *testing.T) {
+	tests := map[uint64]string{
+		512 << 20:  "512M",
+		1536 << 20: "1.5G",
+		4096:       "4K",
+		100:        "100B",
+	}
+	for in, want := range tests {
+		if got := shortSize(in); got != want {
+			t.Errorf("shortSize(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRestartCounts(t *testing.T) {
+	boot := time.Now().Add(-72 * time.Hour)
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd", StartedAt: boot},
+		{PID: 50, Command: "supervisord", Cmdline: "/usr/bin/python3 /usr/bin/supervisord", StartedAt: boot},
+		{PID: 60, Command: "sh", StartedAt: boot.Add(70 * time.Hour)},
+		{PID: 61, Command: "sh", StartedAt: boot.Add(70 * time.Hour)},
+		{PID: 62, Command: "app", StartedAt: boot.Add(70 * time.Hour)},
+	}
+
+	if got := NestedSameName(ancestry); got != 1 {
+		t.Errorf("NestedSameName() = %d, want 1", got)
+	}
+	for _, w := range Warnings(ancestry) {
+		if w.ID == "restart-loop" {
+			t.Errorf("nested same-name ancestors reported as restarts: %+v", w)
+		}
+	}
+
+	src := model.Source{Type: model.SourceSupervisor, Name: "supervisord"}
+	if got := SupervisorStarted(src, ancestry); got == nil || !got.Equal(boot) {
+		t.Errorf("SupervisorStarted() = %v, want %v", got, boot)
+	}
+	if got := SupervisorStarted(model.Source{Type: model.SourceSupervisor, Name: "pm2"}, ancestry); got != nil {
+		t.Errorf("SupervisorStarted(pm2) = %v, want nil", got)
+	}
+
+	if w := RestartWarnings(3, "systemd NRestarts of app.service"); w != nil {
+		t.Errorf("RestartWarnings(3) = %+v, want none", w)
+	}
+	w := RestartWarnings(12, "systemd NRestarts of app.service")
+	if len(w) != 1 || w[0].ID != "restart-loop" || w[0].Evidence != "12 restarts (systemd NRestarts of app.service)" {
+		t.Errorf("RestartWarnings(12) = %+v", w)
+	}
+}
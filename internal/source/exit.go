@@ -0,0 +1,147 @@
+package source
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// crashSignals are signals raised by the process's own faults
+var crashSignals = map[int]bool{4: true, 6: true, 7: true, 8: true, 11: true, 31: true}
+
+// ExitReason concludes why a process or unit stopped, in order of
+// authority: an OOM kill in the kernel log, the exit status recorded by
+// witr record, then the result systemd keeps for the unit
+func ExitReason(r model.ExitReport) string {
+	if n := len(r.OOMKills); n > 0 {
+		k := r.OOMKills[n-1]
+		if k.MemCgroup != "" && k.MemCgroup != "/" {
+			return fmt.Sprintf("killed by the kernel OOM killer: memory cgroup %s hit its limit", k.MemCgroup)
+		}
+		return "killed by the kernel OOM killer: the system ran out of memory"
+	}
+
+	if r.Run != nil && r.Run.Exit != nil {
+		if reason := statusReason(r.Run.Exit.ExitCode, r.Run.Exit.Signal, r.Run.Exit.CoreDumped); reason != "" {
+			return reason
+		}
+	}
+
+	if u := r.Unit; u != nil {
+		switch u.Result {
+		case "oom-kill":
+			return "killed by the OOM killer (systemd result oom-kill)"
+		case "timeout":
+			return "systemd gave up waiting for it to start or stop (timeout)"
+		case "watchdog":
+			return "killed by systemd after missing its watchdog keep-alive"
+		case "start-limit-hit":
+			return "failed and restarted too often, systemd stopped trying (start-limit-hit)"
+		case "exit-code", "signal", "core-dump":
+			if reason := unitStatusReason(u); reason != "" {
+				return reason
+			}
+			return "failed (systemd result " + u.Result + ")"
+		case "success":
+			if u.ActiveState == "active" || u.ActiveState == "activating" || u.ActiveState == "reloading" {
+				return "running"
+			}
+			if u.ExecMainCode == "killed" && (u.ExecMainStatus == 15 || u.ExecMainStatus == 2) {
+				return "stopped normally (systemctl stop or shutdown)"
+			}
+			if reason := unitStatusReason(u); reason != "" {
+				return reason
+			}
+			return "stopped normally"
+		case "":
+		default:
+			return "failed (systemd result " + u.Result + ")"
+		}
+	}
+
+	if r.Run != nil && r.Run.Exit != nil {
+		return "exited, the exit status was not recorded (witr record was polling /proc)"
+	}
+	return "unknown"
+}
+
+// RunStart is when a recorded run was first exec'd, zero if unknown
+func RunStart(run *model.HistoryRun) time.Time {
+	if run == nil || len(run.Execs) == 0 {
+		return time.Time{}
+	}
+	return run.Execs[0].Time
+}
+
+// oomLogSlack allows for the exit being recorded a little after the kernel
+// logged the kill
+const oomLogSlack = time.Minute
+
+// OOMKillsDuring keeps the OOM kills logged while a recorded run was alive,
+// so a kill of an earlier process with the same PID is not taken for its
+// end. Without a recorded start nothing tells them apart and all are kept.
+func OOMKillsDuring(kills []model.OOMKill, run *model.HistoryRun) []model.OOMKill {
+	start := RunStart(run)
+	if start.IsZero() {
+		return kills
+	}
+	var out []model.OOMKill
+	for _, k := range kills {
+		if k.Time.IsZero() || k.Time.Before(start) {
+			continue
+		}
+		if run.Exit != nil && k.Time.After(run.Exit.Time.Add(oomLogSlack)) {
+			continue
+		}
+		out = append(out, k)
+	}
+	return out
+}
+
+func unitStatusReason(u *model.UnitState) string {
+	switch u.ExecMainCode {
+	case "exited":
+		code := u.ExecMainStatus
+		return statusReason(&code, 0, false)
+	case "killed":
+		return statusReason(nil, u.ExecMainStatus, false)
+	case "dumped":
+		return statusReason(nil, u.ExecMainStatus, true)
+	}
+	return ""
+}
+
+func statusReason(code *int, signal int, coreDumped bool) string {
+	switch {
+	case signal != 0 && (coreDumped || crashSignals[signal]):
+		reason := "crashed with " + model.SignalName(signal)
+		if coreDumped {
+			reason += ", core dumped"
+		}
+		return reason
+	case signal != 0:
+		return "killed by " + model.SignalName(signal)
+	case code != nil && *code == 0:
+		return "exited normally (exit code 0)"
+	case code != nil:
+		return fmt.Sprintf("exited with code %d", *code)
+	}
+	return ""
+}
+
+// UnitFromCgroup returns the systemd unit a cgroup path belongs to, e.g.
+// "app.service" for "/system.slice/app.service" or ".../app.service/worker"
+func UnitFromCgroup(cgroup string) string {
+	for dir := cgroup; dir != "/" && dir != "." && dir != ""; dir = path.Dir(dir) {
+		base := path.Base(dir)
+		for _, suffix := range []string{".service", ".scope"} {
+			if strings.HasSuffix(base, suffix) {
+				return base
+			}
+		}
+	}
+	return ""
+}
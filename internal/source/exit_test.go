@@ -0,0 +1,111 @@
+package source
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestExitReason(t *testing.T) {
+	code := func(n int) *int { return &n }
+	tests := []struct {
+		name   string
+		report model.ExitReport
+		want   string
+	}{
+		{
+			name: "oom kill in a cgroup wins",
+			report: model.ExitReport{
+				OOMKills: []model.OOMKill{{PID: 10, MemCgroup: "/system.slice/app.service"}},
+				Run:      &model.HistoryRun{Exit: &model.HistoryEvent{Signal: 9}},
+			},
+			want: "killed by the kernel OOM killer: memory cgroup /system.slice/app.service hit its limit",
+		},
+		{
+			name:   "recorded crash",
+			report: model.ExitReport{Run: &model.HistoryRun{Exit: &model.HistoryEvent{Signal: 11, CoreDumped: true}}},
+			want:   "crashed with SIGSEGV (11), core dumped",
+		},
+		{
+			name:   "recorded exit code",
+			report: model.ExitReport{Run: &model.HistoryRun{Exit: &model.HistoryEvent{ExitCode: code(2)}}},
+			want:   "exited with code 2",
+		},
+		{
+			name:   "unit stopped by systemctl",
+			report: model.ExitReport{Unit: &model.UnitState{ActiveState: "inactive", Result: "success", ExecMainCode: "killed", ExecMainStatus: 15}},
+			want:   "stopped normally (systemctl stop or shutdown)",
+		},
+		{
+			name:   "unit failed with exit code",
+			report: model.ExitReport{Unit: &model.UnitState{ActiveState: "failed", Result: "exit-code", ExecMainCode: "exited", ExecMainStatus: 1}},
+			want:   "exited with code 1",
+		},
+		{
+			name:   "unit restarted too often",
+			report: model.ExitReport{Unit: &model.UnitState{ActiveState: "failed", Result: "start-limit-hit"}},
+			want:   "failed and restarted too often, systemd stopped trying (start-limit-hit)",
+		},
+		{
+			name:   "polled exit",
+			report: model.ExitReport{Run: &model.HistoryRun{Exit: &model.HistoryEvent{}}},
+			want:   "exited, the exit status was not recorded (witr record was polling /proc)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitReason(tt.report); got != tt.want {
+				t.Errorf("ExitReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitFromCgroup(t *testing.T) {
+	tests := map[string]string{
+		"/system.slice/app.service":                                       "app.service",
+		"/system.slice/app.service/worker":                                "app.service",
+		"/user.slice/user-1000.slice/user@1000.service/app.slice/x.scope": "x.scope",
+		"/":                "",
+		"/docker/0123abcd": "",
+	}
+	for cgroup, want := range tests {
+		if got := UnitFromCgroup(cgroup); got != want {
+			t.Errorf("UnitFromCgroup(%q) = %q, want %q", cgroup, got, want)
+		}
+	}
+}
+
+func TestOOMKillsDuring(t *testing.T) {
+	start := time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC)
+	kills := []model.OOMKill{
+		{PID: 42, Command: "old", Time: start.Add(-time.Hour)},
+		{PID: 42, Command: "unknown"},
+		{PID: 42, Command: "java", Time: start.Add(10 * time.Minute)},
+		{PID: 42, Command: "later", Time: start.Add(2 * time.Hour)},
+	}
+	run := &model.HistoryRun{
+		Execs: []model.HistoryEvent{{Time: start}},
+		Exit:  &model.HistoryEvent{Time: start.Add(10*time.Minute + time.Second)},
+	}
+
+	commands := func(kills []model.OOMKill) []string {
+		var out []string
+		for _, k := range kills {
+			out = append(out, k.Command)
+		}
+		return out
+	}
+	if got := commands(OOMKillsDuring(kills, run)); !slices.Equal(got, []string{"java"}) {
+		t.Errorf("OOMKillsDuring() = %v, want [java]", got)
+	}
+	// Without a recorded start, an earlier process with the PID cannot be told apart
+	if got := OOMKillsDuring(kills, &model.HistoryRun{Exit: run.Exit}); len(got) != len(kills) {
+		t.Errorf("OOMKillsDuring(no exec) = %v, want all", commands(got))
+	}
+	if got := OOMKillsDuring(kills, nil); len(got) != len(kills) {
+		t.Errorf("OOMKillsDuring(nil) = %v, want all", commands(got))
+	}
+}
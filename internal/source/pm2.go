@@ -0,0 +1,173 @@
+package source
+
+import (
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// The god daemon titles itself "PM2 v5.3.0: God Daemon (/home/app/.pm2)"
+var pm2DaemonPattern = regexp.MustCompile(`God Daemon \(([^)]+)\)`)
+
+// pm2App is the subset of a dump.pm2 entry witr reports
+type pm2App struct {
+	Name        string `json:"name"`
+	ID          *int   `json:"pm_id"`
+	ExecMode    string `json:"exec_mode"`
+	RestartTime *int   `json:"restart_time"`
+	Autorestart *bool  `json:"autorestart"`
+	Watch       any    `json:"watch"`
+	OutLogPath  string `json:"pm_out_log_path"`
+	ErrLogPath  string `json:"pm_err_log_path"`
+}
+
+// pm2Details describes the pm2 app a process belongs to. The app is found
+// through ~/.pm2/pids/<name>-<id>.pid and its settings through ~/.pm2/dump.pm2
+// of the user running the god daemon. pm2 also exports its settings into the
+// environment of each app it starts; those are current for the running
+// instance and win over dump.pm2, which is only rewritten by "pm2 save".
+func pm2Details(ancestry []model.Process, daemonIdx int) map[string]string {
+	home := pm2Home(ancestry[daemonIdx])
+	if home == "" {
+		return nil
+	}
+
+	// The app process is the one the daemon spawned, i.e. right below it
+	var app model.Process
+	if daemonIdx+1 < len(ancestry) {
+		app = ancestry[daemonIdx+1]
+	} else {
+		return map[string]string{"home": home}
+	}
+
+	name, id := pm2FindPid(filepath.Join(home, "pids"), app.PID)
+	if name == "" {
+		name = envValue(app.Env, "name")
+		id = envValue(app.Env, "pm_id")
+	}
+	d := details("home", home, "app", name, "id", id)
+	if name == "" {
+		return d
+	}
+
+	if entry, ok := pm2FindApp(filepath.Join(home, "dump.pm2"), name, id); ok {
+		mergeDetails(d, entry)
+	}
+	mergeDetails(d, details(
+		"restarts", envValue(app.Env, "restart_time"),
+		"exec_mode", strings.TrimSuffix(envValue(app.Env, "exec_mode"), "_mode"),
+	))
+	return d
+}
+
+// pm2Home finds the PM2_HOME of the god daemon: from its process title, its
+// environment, or the default ~/.pm2 of the user running it
+func pm2Home(daemon model.Process) string {
+	if m := pm2DaemonPattern.FindStringSubmatch(daemon.Cmdline); m != nil {
+		return m[1]
+	}
+	if home := envValue(daemon.Env, "PM2_HOME"); home != "" {
+		return home
+	}
+	if daemon.User == "" {
+		return ""
+	}
+	u, err := user.Lookup(daemon.User)
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(u.HomeDir, ".pm2")
+}
+
+// pm2FindPid returns the app name and id whose pid file holds pid. Pid files
+// are named <name>-<id>.pid and the name may itself contain dashes.
+func pm2FindPid(dir string, pid int) (string, string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", ""
+	}
+	want := strconv.Itoa(pid)
+	for _, e := range entries {
+		base, ok := strings.CutSuffix(e.Name(), ".pid")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil || strings.TrimSpace(string(data)) != want {
+			continue
+		}
+		if i := strings.LastIndexByte(base, '-'); i > 0 {
+			return base[:i], base[i+1:]
+		}
+		return base, ""
+	}
+	return "", ""
+}
+
+// pm2FindApp reads the settings of an app from dump.pm2
+func pm2FindApp(dumpFile, name, id string) (map[string]string, bool) {
+	data, err := os.ReadFile(dumpFile)
+	if err != nil {
+		return nil, false
+	}
+	return parsePM2Dump(data, name, id)
+}
+
+func parsePM2Dump(data []byte, name, id string) (map[string]string, bool) {
+	var apps []pm2App
+	if err := json.Unmarshal(data, &apps); err != nil {
+		return nil, false
+	}
+	for _, a := range apps {
+		if a.Name != name {
+			continue
+		}
+		if id != "" && a.ID != nil && strconv.Itoa(*a.ID) != id {
+			continue
+		}
+		d := details(
+			"exec_mode", strings.TrimSuffix(a.ExecMode, "_mode"),
+			"out_log", a.OutLogPath,
+			"err_log", a.ErrLogPath,
+		)
+		if a.ID != nil {
+			d["id"] = strconv.Itoa(*a.ID)
+		}
+		if a.RestartTime != nil {
+			d["restarts"] = strconv.Itoa(*a.RestartTime)
+		}
+		if a.Autorestart != nil {
+			d["autorestart"] = strconv.FormatBool(*a.Autorestart)
+		}
+		if w := pm2Watch(a.Watch); w != "" {
+			d["watch"] = w
+		}
+		return d, true
+	}
+	return nil, false
+}
+
+// pm2Watch formats the watch setting, which is either a bool or a list of paths
+func pm2Watch(v any) string {
+	switch w := v.(type) {
+	case bool:
+		return strconv.FormatBool(w)
+	case []any:
+		var paths []string
+		for _, p := range w {
+			if s, ok := p.(string); ok {
+				paths = append(paths, s)
+			}
+		}
+		return strings.Join(paths, ", ")
+	case string:
+		return w
+	}
+	return ""
+}
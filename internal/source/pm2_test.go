@@ -0,0 +1,74 @@
+package source
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const testPM2Dump = `[
+  {"name": "worker", "pm_id": 0, "exec_mode": "fork_mode", "restart_time": 2, "autorestart": true, "watch": false,
+   "pm_out_log_path": "/home/app/.pm2/logs/worker-out.log", "pm_err_log_path": "/home/app/.pm2/logs/worker-error.log"},
+  {"name": "my-api", "pm_id": 1, "exec_mode": "cluster_mode", "restart_time": 7, "autorestart": false, "watch": ["src", "config"],
+   "pm_out_log_path": "/home/app/.pm2/logs/my-api-out-1.log", "pm_err_log_path": "/home/app/.pm2/logs/my-api-error-1.log"}
+]`
+
+func TestPM2Details(t *testing.T) {
+	home := t.TempDir()
+	if err := os.Mkdir(filepath.Join(home, "pids"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"dump.pm2":          testPM2Dump,
+		"pids/worker-0.pid": "4100\n",
+		"pids/my-api-1.pid": "4200",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(home, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	daemon := model.Process{PID: 4000, Command: "PM2 v5.3.0: God", Cmdline: "PM2 v5.3.0: God Daemon (" + home + ")"}
+
+	tests := []struct {
+		name string
+		app  model.Process
+		want map[string]string
+	}{
+		{
+			name: "from pid file and dump",
+			app:  model.Process{PID: 4200, Command: "node"},
+			want: map[string]string{
+				"home": home, "app": "my-api", "id": "1", "exec_mode": "cluster", "restarts": "7",
+				"autorestart": "false", "watch": "src, config",
+				"out_log": "/home/app/.pm2/logs/my-api-out-1.log", "err_log": "/home/app/.pm2/logs/my-api-error-1.log",
+			},
+		},
+		{
+			name: "environment wins over a stale dump",
+			app:  model.Process{PID: 4100, Command: "node", Env: []string{"name=worker", "pm_id=0", "restart_time=5"}},
+			want: map[string]string{
+				"home": home, "app": "worker", "id": "0", "exec_mode": "fork", "restarts": "5",
+				"autorestart": "true", "watch": "false",
+				"out_log": "/home/app/.pm2/logs/worker-out.log", "err_log": "/home/app/.pm2/logs/worker-error.log",
+			},
+		},
+		{
+			name: "unknown app",
+			app:  model.Process{PID: 4300, Command: "node"},
+			want: map[string]string{"home": home},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pm2Details([]model.Process{daemon, tt.app}, 0)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("pm2Details() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package source
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// capabilities that effectively grant root-equivalent control over the host
+var dangerousCapabilities = []string{
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_RAWIO",
+	"CAP_DAC_OVERRIDE",
+	"CAP_NET_ADMIN",
+	"CAP_BPF",
+}
+
+// rootWarning grades the "running as root" finding by how much the process can actually do
+func rootWarning(sec *model.SecurityContext) model.Warning {
+	w := model.Warning{
+		ID:       "root-user",
+		Severity: model.SeverityInfo,
+		Message:  "Process is running as root",
+	}
+	if sec == nil {
+		return w
+	}
+
+	w.Evidence = securityEvidence(sec)
+	if slices.Contains(sec.CapEff, "CAP_SYS_ADMIN") && !sec.Confined() {
+		w.Severity = model.SeverityWarn
+		w.Message = "Process is running as root with full capabilities and no seccomp or LSM confinement"
+	}
+	return w
+}
+
+func securityWarnings(p model.Process) []model.Warning {
+	sec := p.Security
+	if sec == nil {
+		return nil
+	}
+
+	var w []model.Warning
+
+	if sec.UID > 0 {
+		var held []string
+		for _, c := range dangerousCapabilities {
+			if slices.Contains(sec.CapEff, c) {
+				held = append(held, c)
+			}
+		}
+		if len(held) > 0 {
+			w = append(w, model.Warning{
+				ID:       "nonroot-privileged-caps",
+				Severity: model.SeverityWarn,
+				Message:  "Non-root process holds " + strings.Join(held, ", "),
+				Evidence: "uid=" + strconv.Itoa(sec.UID),
+			})
+		}
+	}
+
+	if IsPublicBind(p.BindAddresses) && !sec.Confined() {
+		w = append(w, model.Warning{
+			ID:       "unconfined-public",
+			Severity: model.SeverityWarn,
+			Message:  "Unconfined process is listening on a public interface (no seccomp filter or LSM profile)",
+			Evidence: securityEvidence(sec),
+		})
+	}
+
+	return w
+}
+
+func securityEvidence(sec *model.SecurityContext) string {
+	lsm := sec.LSMLabel
+	if lsm == "" {
+		lsm = "none"
+	}
+	return "caps=" + strconv.Itoa(len(sec.CapEff)) + " seccomp=" + sec.Seccomp + " lsm=" + lsm
+}
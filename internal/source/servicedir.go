@@ -0,0 +1,64 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// serviceDirDetails describes the runit or s6 service a process runs under.
+// runsv and s6-supervise run with the service directory as their cwd and
+// its name as their argument; the directory holds the run script, an
+// optional down file (not started at boot) and the supervise/ state.
+func serviceDirDetails(ancestry []model.Process) map[string]string {
+	for _, p := range ancestry {
+		if p.Command != "runsv" && p.Command != "s6-supervise" {
+			continue
+		}
+		dir := serviceDir(p)
+		if dir == "" {
+			return nil
+		}
+		return readServiceDir(dir)
+	}
+	return nil
+}
+
+func serviceDir(supervise model.Process) string {
+	if supervise.WorkingDir != "" && supervise.WorkingDir != "unknown" {
+		return supervise.WorkingDir
+	}
+	if fields := strings.Fields(supervise.Cmdline); len(fields) > 1 && filepath.IsAbs(fields[1]) {
+		return fields[1]
+	}
+	return ""
+}
+
+func readServiceDir(dir string) map[string]string {
+	run := filepath.Join(dir, "run")
+	if _, err := os.Stat(run); err != nil {
+		return nil
+	}
+	d := map[string]string{
+		"name":        filepath.Base(dir),
+		"config":      run,
+		"autostart":   "true",
+		"autorestart": "true",
+	}
+	if _, err := os.Stat(filepath.Join(dir, "down")); err == nil {
+		d["autostart"] = "false (down file)"
+	}
+
+	// runit keeps a readable state, e.g. "run", "down, normally up, want up" or
+	// "run, want down" while stopping; s6 only has the binary supervise/status
+	if data, err := os.ReadFile(filepath.Join(dir, "supervise", "stat")); err == nil {
+		state := strings.TrimSpace(string(data))
+		d["state"] = state
+		if strings.Contains(state, "want down") || strings.Contains(state, "want exit") {
+			d["autorestart"] = "false"
+		}
+	}
+	return d
+}
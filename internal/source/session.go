@@ -0,0 +1,148 @@
+package source
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// userSwitchers change the user of the command they run
+var userSwitchers = map[string]bool{
+	"sudo":    true,
+	"su":      true,
+	"doas":    true,
+	"pkexec":  true,
+	"runuser": true,
+}
+
+// DetectSession reports the interactive login a process was started from:
+// the logged in user (audit loginuid, SUDO_USER or the sshd session), the
+// SSH client address, user switches and the tmux or screen session. It
+// returns nil for processes with no sign of a login. cmdline reads the
+// command line of a process outside the ancestry, such as the tmux server.
+func DetectSession(ancestry []model.Process, cmdline func(pid int) string) *model.Session {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	s := &model.Session{}
+
+	// Environment is inherited, the nearest process that has a variable wins
+	env := func(key string) string {
+		for i := len(ancestry) - 1; i >= 0; i-- {
+			if v := envValue(ancestry[i].Env, key); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	sshUser := ""
+	for _, p := range ancestry {
+		if user, ok := sshdSessionUser(p); ok {
+			sshUser = user
+		}
+		if userSwitchers[p.Command] && (len(s.Via) == 0 || s.Via[len(s.Via)-1] != p.Command) {
+			s.Via = append(s.Via, p.Command)
+		}
+	}
+
+	for _, key := range []string{"SSH_CONNECTION", "SSH_CLIENT"} {
+		if fields := strings.Fields(env(key)); len(fields) > 0 {
+			s.RemoteAddr = fields[0]
+			break
+		}
+	}
+
+	sudoUser := env("SUDO_USER")
+	if sudoUser != "" && !slices.Contains(s.Via, "sudo") {
+		s.Via = append([]string{"sudo"}, s.Via...)
+	}
+
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		if login := ancestry[i].Login; login != nil {
+			s.User = login.User
+			s.SessionID = login.SessionID
+			break
+		}
+	}
+	if s.User == "" {
+		s.User = sudoUser
+	}
+	if s.User == "" {
+		s.User = sshUser
+	}
+
+	if tmux := env("TMUX"); tmux != "" {
+		// TMUX is "<socket>,<server pid>,<session index>". The socket is
+		// never connected to: whoever owns the process sets the variable,
+		// and a server listening there could feed a root tmux client
+		// commands to run.
+		s.Multiplexer = "tmux"
+		if parts := strings.Split(tmux, ","); len(parts) > 1 {
+			s.MultiplexerSession = tmuxServerSession(ancestry, parts[1], cmdline)
+		}
+	} else if sty := env("STY"); sty != "" {
+		// STY is "<pid>.<name>", the name defaulting to "<tty>.<host>"
+		s.Multiplexer = "screen"
+		if _, name, ok := strings.Cut(sty, "."); ok {
+			s.MultiplexerSession = name
+		}
+	} else {
+		for _, p := range ancestry {
+			if strings.HasPrefix(p.Command, "tmux") {
+				s.Multiplexer = "tmux"
+			} else if p.Command == "screen" || p.Command == "SCREEN" {
+				s.Multiplexer = "screen"
+			}
+		}
+	}
+
+	if s.User == "" && s.RemoteAddr == "" && len(s.Via) == 0 && s.Multiplexer == "" {
+		return nil
+	}
+	return s
+}
+
+// sshdSessionUser extracts the user from an sshd session process title,
+// "sshd: alice@pts/0", "sshd: alice [priv]" or "sshd-session: alice@notty"
+func sshdSessionUser(p model.Process) (string, bool) {
+	for _, prefix := range []string{"sshd: ", "sshd-session: "} {
+		rest, ok := strings.CutPrefix(p.Cmdline, prefix)
+		if !ok {
+			continue
+		}
+		user, _, _ := strings.Cut(rest, "@")
+		user, _, _ = strings.Cut(user, " ")
+		if user == "" || strings.HasPrefix(user, "/") {
+			return "", false
+		}
+		return user, true
+	}
+	return "", false
+}
+
+// tmuxServerSession names the session from the command that started the
+// tmux server ("tmux new -s deploy"), found in the ancestry or else read
+// with cmdline. Anything but a tmux command line is ignored.
+func tmuxServerSession(ancestry []model.Process, serverPID string, cmdline func(pid int) string) string {
+	pid, err := strconv.Atoi(serverPID)
+	if err != nil || pid <= 0 {
+		return ""
+	}
+	server := ""
+	for _, p := range ancestry {
+		if p.PID == pid {
+			server = p.Cmdline
+		}
+	}
+	if server == "" && cmdline != nil {
+		server = cmdline(pid)
+	}
+	if fields := strings.Fields(server); len(fields) == 0 || !strings.HasPrefix(filepath.Base(fields[0]), "tmux") {
+		return ""
+	}
+	return argValue(server, "-s", "-t")
+}
@@ -0,0 +1,97 @@
+package source
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectSession(t *testing.T) {
+	cmdlines := map[int]string{
+		850: "/usr/bin/tmux new-session -s ops",
+		1:   "/sbin/init splash -s x",
+	}
+	cmdline := func(pid int) string { return cmdlines[pid] }
+
+	tests := []struct {
+		name     string
+		ancestry []model.Process
+		want     *model.Session
+	}{
+		{
+			name: "ssh, sudo and tmux",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 800, Command: "tmux: server", Cmdline: "tmux new -s deploy"},
+				{PID: 801, Command: "bash", Login: &model.LoginInfo{UID: 1000, User: "alice", SessionID: 4},
+					Env: []string{"SSH_CONNECTION=10.0.0.5 51234 10.0.0.1 22", "TMUX=/tmp/tmux-1000/default,800,0", "TMUX_PANE=%3"}},
+				{PID: 900, Command: "sudo", Login: &model.LoginInfo{UID: 1000, User: "alice", SessionID: 4}},
+				{PID: 901, Command: "python3", User: "root", Login: &model.LoginInfo{UID: 1000, User: "alice", SessionID: 4},
+					Env: []string{"SUDO_USER=alice", "TMUX=/tmp/tmux-1000/default,800,0", "TMUX_PANE=%3"}},
+			},
+			want: &model.Session{User: "alice", RemoteAddr: "10.0.0.5", SessionID: 4, Via: []string{"sudo"}, Multiplexer: "tmux", MultiplexerSession: "deploy"},
+		},
+		{
+			name: "tmux name from the server command without a pane",
+			ancestry: []model.Process{
+				{PID: 800, Command: "tmux: server", Cmdline: "tmux new-session -d -s builds"},
+				{PID: 801, Command: "make", Env: []string{"TMUX=/tmp/tmux-0/default,800,1"}},
+			},
+			want: &model.Session{Multiplexer: "tmux", MultiplexerSession: "builds"},
+		},
+		{
+			name: "tmux server outside the ancestry",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 901, Command: "make", Env: []string{"TMUX=/tmp/tmux-1000/default,850,0", "TMUX_PANE=%1"}},
+			},
+			want: &model.Session{Multiplexer: "tmux", MultiplexerSession: "ops"},
+		},
+		{
+			name: "TMUX pointing at a process that is not tmux",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 902, Command: "sleep", Env: []string{"TMUX=/tmp/evil.sock,1,0"}},
+			},
+			want: &model.Session{Multiplexer: "tmux"},
+		},
+		{
+			name: "sshd session and su without loginuid",
+			ancestry: []model.Process{
+				{PID: 700, Command: "sshd", Cmdline: "sshd: /usr/sbin/sshd -D [listener] 0 of 10-100 startups"},
+				{PID: 701, Command: "sshd", Cmdline: "sshd: bob [priv]"},
+				{PID: 702, Command: "sshd", Cmdline: "sshd: bob@pts/1", Env: []string{"SSH_CLIENT=192.168.1.20 40022 22"}},
+				{PID: 703, Command: "bash"},
+				{PID: 704, Command: "su"},
+				{PID: 705, Command: "bash"},
+			},
+			want: &model.Session{User: "bob", RemoteAddr: "192.168.1.20", Via: []string{"su"}},
+		},
+		{
+			name: "screen",
+			ancestry: []model.Process{
+				{PID: 600, Command: "SCREEN"},
+				{PID: 601, Command: "bash", Env: []string{"STY=600.pts-0.build01"}},
+			},
+			want: &model.Session{Multiplexer: "screen", MultiplexerSession: "pts-0.build01"},
+		},
+		{
+			name: "service",
+			ancestry: []model.Process{
+				{PID: 1, Command: "systemd"},
+				{PID: 500, Command: "nginx", Env: []string{"PATH=/usr/bin"}},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectSession(tt.ancestry, cmdline)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectSession() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,17 @@
 package source
 
 import (
+	"path"
 	"strings"
+	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 var knownSupervisors = map[string]string{
 	"pm2":          "pm2",
-	"pm2 god":      "pm2",
 	"supervisord":  "supervisord",
 	"supervisor":   "supervisord",
-	"gunicorn":     "gunicorn",
-	"uwsgi":        "uwsgi",
 	"s6-supervise": "s6",
 	"s6":           "s6",
 	"s6-svscan":    "s6",
@@ -38,6 +37,25 @@ var knownSupervisors = map[string]string{
 	"nssm":         "nssm",
 }
 
+// uncountedSupervisors restart their programs without counting the restarts
+var uncountedSupervisors = map[string]bool{"supervisord": true, "runit": true, "s6": true}
+
+// SupervisorStarted returns when the supervisor of a process started, for
+// supervisors that keep no restart count. The nearest one in the ancestry is
+// taken: for runit and s6 that is the per-service runsv or s6-supervise.
+func SupervisorStarted(src model.Source, ancestry []model.Process) *time.Time {
+	if src.Type != model.SourceSupervisor || !uncountedSupervisors[src.Name] {
+		return nil
+	}
+	for i := len(ancestry) - 2; i >= 0; i-- {
+		if label, ok := supervisorLabel(ancestry[i]); ok && label == src.Name {
+			started := ancestry[i].StartedAt
+			return &started
+		}
+	}
+	return nil
+}
+
 func detectSupervisor(ancestry []model.Process) *model.Source {
 	// Check if there's a shell in the ancestry
 	hasShell := false
@@ -48,14 +66,15 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 		}
 	}
 
-	for _, p := range ancestry {
+	for i, p := range ancestry {
 		// Normalize: remove spaces, lowercase
 		pname := strings.ReplaceAll(strings.ToLower(p.Command), " ", "")
 		pcmd := strings.ReplaceAll(strings.ToLower(p.Cmdline), " ", "")
 		if strings.Contains(pname, "pm2") || strings.Contains(pcmd, "pm2") {
 			return &model.Source{
-				Type: model.SourceSupervisor,
-				Name: "pm2",
+				Type:    model.SourceSupervisor,
+				Name:    "pm2",
+				Details: pm2Details(ancestry, i),
 			}
 		}
 
@@ -71,30 +90,50 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 			}
 		}
 
-		if label, ok := knownSupervisors[strings.ToLower(p.Command)]; ok {
+		if label, ok := supervisorLabel(p); ok {
 			// Skip "init" if there's a shell in the ancestry
 			// This allows shell-launched processes to be detected as shell rather than init
 			if label == "init" && hasShell {
 				continue
 			}
 			return &model.Source{
-				Type: model.SourceSupervisor,
-				Name: label,
-			}
-		}
-		// Also match on command line for supervisor keywords
-		for sup, label := range knownSupervisors {
-			if strings.Contains(strings.ToLower(p.Cmdline), sup) {
-				// Skip "init" if there's a shell in the ancestry
-				if label == "init" && hasShell {
-					continue
-				}
-				return &model.Source{
-					Type: model.SourceSupervisor,
-					Name: label,
-				}
+				Type:    model.SourceSupervisor,
+				Name:    label,
+				Details: supervisorDetails(label, ancestry),
 			}
 		}
 	}
 	return nil
 }
+
+// supervisorDetails looks up the program or service definition for
+// supervisors whose configuration witr can read
+func supervisorDetails(label string, ancestry []model.Process) map[string]string {
+	switch label {
+	case "supervisord":
+		return supervisordDetails(ancestry)
+	case "runit", "s6":
+		return serviceDirDetails(ancestry)
+	}
+	return nil
+}
+
+// supervisorLabel matches the command, the executable name from the command
+// line and the app an interpreter runs (ruby bin/god) against known supervisors.
+// Names are compared whole so "god" does not match "godoc" or /home/godfrey.
+func supervisorLabel(p model.Process) (string, bool) {
+	names := []string{p.Command}
+	if fields := strings.Fields(p.Cmdline); len(fields) > 0 {
+		names = append(names, path.Base(fields[0]))
+	}
+	if p.App != "" {
+		app, _, _ := strings.Cut(p.App, " ")
+		names = append(names, path.Base(app))
+	}
+	for _, name := range names {
+		if label, ok := knownSupervisors[strings.ToLower(name)]; ok {
+			return label, true
+		}
+	}
+	return "", false
+}
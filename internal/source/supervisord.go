@@ -0,0 +1,194 @@
+package source
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// supervisordDefaultConfigs is where supervisord looks for its config without -c,
+// after the cwd-relative supervisord.conf and etc/supervisord.conf
+var supervisordDefaultConfigs = []string{
+	"/etc/supervisord.conf",
+	"/etc/supervisor/supervisord.conf",
+}
+
+// iniSection is one [section] of a supervisord config file
+type iniSection struct {
+	name   string
+	file   string
+	values map[string]string
+}
+
+// supervisordDetails finds the [program:x] section that started the process
+// below supervisord. supervisord exports SUPERVISOR_PROCESS_NAME to its
+// children; without it the program command is matched against the cmdline.
+func supervisordDetails(ancestry []model.Process) map[string]string {
+	idx := -1
+	for i, p := range ancestry {
+		if label, ok := supervisorLabel(p); ok && label == "supervisord" {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 || idx+1 >= len(ancestry) {
+		return nil
+	}
+	daemon, child := ancestry[idx], ancestry[idx+1]
+
+	config := supervisordConfig(daemon)
+	if config == "" {
+		return nil
+	}
+	sections := readSupervisordConfig(config)
+
+	name := envValue(child.Env, "SUPERVISOR_PROCESS_NAME")
+	group := envValue(child.Env, "SUPERVISOR_GROUP_NAME")
+	for _, sec := range sections {
+		program, ok := strings.CutPrefix(sec.name, "program:")
+		if !ok {
+			continue
+		}
+		switch {
+		case name != "":
+			// process_name may add a suffix (app_00); the group is the program name
+			if program != name && program != group {
+				continue
+			}
+		case !commandMatches(sec.values["command"], child.Cmdline):
+			continue
+		}
+		d := details(
+			"name", program,
+			"config", sec.file,
+			"autorestart", sec.values["autorestart"],
+			"startretries", sec.values["startretries"],
+		)
+		// supervisord defaults
+		if d["autorestart"] == "" {
+			d["autorestart"] = "unexpected"
+		}
+		if d["startretries"] == "" {
+			d["startretries"] = "3"
+		}
+		return d
+	}
+	return details("name", name, "config", config)
+}
+
+// supervisordConfig returns the config file supervisord was started with
+func supervisordConfig(daemon model.Process) string {
+	config := argValue(daemon.Cmdline, "-c", "--configuration")
+	if config != "" {
+		if !filepath.IsAbs(config) && daemon.WorkingDir != "" && daemon.WorkingDir != "unknown" {
+			config = filepath.Join(daemon.WorkingDir, config)
+		}
+		return config
+	}
+	candidates := supervisordDefaultConfigs
+	if daemon.WorkingDir != "" && daemon.WorkingDir != "unknown" {
+		candidates = append([]string{
+			filepath.Join(daemon.WorkingDir, "supervisord.conf"),
+			filepath.Join(daemon.WorkingDir, "etc", "supervisord.conf"),
+		}, candidates...)
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return ""
+}
+
+// readSupervisordConfig reads a config file and the files named by its
+// [include] section, in order
+func readSupervisordConfig(config string) []iniSection {
+	data, err := os.ReadFile(config)
+	if err != nil {
+		return nil
+	}
+	sections := parseINI(string(data), config)
+
+	var included []iniSection
+	for _, sec := range sections {
+		if sec.name != "include" {
+			continue
+		}
+		for _, pattern := range strings.Fields(sec.values["files"]) {
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(filepath.Dir(config), pattern)
+			}
+			matches, _ := filepath.Glob(pattern)
+			for _, m := range matches {
+				if data, err := os.ReadFile(m); err == nil {
+					included = append(included, parseINI(string(data), m)...)
+				}
+			}
+		}
+	}
+	return append(sections, included...)
+}
+
+// parseINI parses the ConfigParser dialect supervisord uses: "key = value" or
+// "key: value", ";" and "#" comments, indented continuation lines
+func parseINI(content, file string) []iniSection {
+	var sections []iniSection
+	var cur *iniSection
+	lastKey := ""
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == ';' || trimmed[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			sections = append(sections, iniSection{
+				name:   strings.TrimSpace(trimmed[1 : len(trimmed)-1]),
+				file:   file,
+				values: make(map[string]string),
+			})
+			cur = &sections[len(sections)-1]
+			lastKey = ""
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if i := strings.Index(trimmed, " ;"); i != -1 {
+			trimmed = strings.TrimSpace(trimmed[:i])
+		}
+		if (line[0] == ' ' || line[0] == '\t') && lastKey != "" {
+			cur.values[lastKey] += " " + trimmed
+			continue
+		}
+		sep := strings.IndexAny(trimmed, "=:")
+		if sep == -1 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(trimmed[:sep]))
+		cur.values[key] = strings.TrimSpace(trimmed[sep+1:])
+		lastKey = key
+	}
+	return sections
+}
+
+// commandMatches compares a configured command with a running cmdline,
+// allowing the program to be configured by name and found on PATH
+func commandMatches(command, cmdline string) bool {
+	want, got := strings.Fields(command), strings.Fields(cmdline)
+	if len(want) == 0 || len(want) != len(got) {
+		return false
+	}
+	if want[0] != got[0] && path.Base(want[0]) != path.Base(got[0]) {
+		return false
+	}
+	for i := 1; i < len(want); i++ {
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
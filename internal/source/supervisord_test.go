@@ -0,0 +1,145 @@
+package source
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseINI(t *testing.T) {
+	content := `; main config
+[supervisord]
+logfile = /var/log/supervisord.log
+
+[program:api]
+command=/srv/api/bin/server
+  --port 8080 ; continued
+autorestart: true
+# comment
+startretries = 10 ; inline comment
+`
+	sections := parseINI(content, "/etc/supervisord.conf")
+	if len(sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(sections))
+	}
+	prog := sections[1]
+	want := map[string]string{
+		"command":      "/srv/api/bin/server --port 8080",
+		"autorestart":  "true",
+		"startretries": "10",
+	}
+	if prog.name != "program:api" || !maps.Equal(prog.values, want) {
+		t.Errorf("section = %s %v, want program:api %v", prog.name, prog.values, want)
+	}
+}
+
+func TestSupervisordDetails(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"supervisord.conf": "[supervisord]\n\n[include]\nfiles = conf.d/*.conf\n",
+		"conf.d/web.conf":  "[program:web]\ncommand = gunicorn app:wsgi -b :8000\nautorestart = true\n",
+		"conf.d/jobs.conf": "[program:jobs]\ncommand = python3 jobs.py\nprocess_name = %(program_name)s_%(process_num)02d\nnumprocs = 2\n",
+	})
+	config := filepath.Join(dir, "supervisord.conf")
+	daemon := model.Process{PID: 10, Command: "supervisord", Cmdline: "/usr/bin/python3 /usr/bin/supervisord -c " + config, App: "supervisord"}
+
+	tests := []struct {
+		name  string
+		child model.Process
+		want  map[string]string
+	}{
+		{
+			name:  "matched by command",
+			child: model.Process{PID: 11, Command: "gunicorn", Cmdline: "/usr/local/bin/gunicorn app:wsgi -b :8000"},
+			want: map[string]string{
+				"name": "web", "config": filepath.Join(dir, "conf.d/web.conf"), "autorestart": "true", "startretries": "3",
+			},
+		},
+		{
+			name:  "matched by group from the environment",
+			child: model.Process{PID: 12, Command: "python3", Cmdline: "python3 jobs.py", Env: []string{"SUPERVISOR_PROCESS_NAME=jobs_01", "SUPERVISOR_GROUP_NAME=jobs"}},
+			want: map[string]string{
+				"name": "jobs", "config": filepath.Join(dir, "conf.d/jobs.conf"), "autorestart": "unexpected", "startretries": "3",
+			},
+		},
+		{
+			name:  "no matching program",
+			child: model.Process{PID: 13, Command: "sleep", Cmdline: "sleep 100"},
+			want:  map[string]string{"config": config},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := supervisordDetails([]model.Process{daemon, tt.child})
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("supervisordDetails() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceDirDetails(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"web/run":             "#!/bin/sh\nexec web\n",
+		"web/supervise/stat":  "run\n",
+		"jobs/run":            "#!/bin/sh\nexec jobs\n",
+		"jobs/down":           "",
+		"jobs/supervise/stat": "run, want down\n",
+		"s6svc/run":           "#!/bin/execlineb -P\nweb\n",
+	})
+
+	tests := []struct {
+		name      string
+		supervise model.Process
+		want      map[string]string
+	}{
+		{
+			name:      "runit service",
+			supervise: model.Process{Command: "runsv", Cmdline: "runsv web", WorkingDir: filepath.Join(dir, "web")},
+			want: map[string]string{
+				"name": "web", "config": filepath.Join(dir, "web/run"), "autostart": "true", "autorestart": "true", "state": "run",
+			},
+		},
+		{
+			name:      "runit service going down",
+			supervise: model.Process{Command: "runsv", Cmdline: "runsv jobs", WorkingDir: filepath.Join(dir, "jobs")},
+			want: map[string]string{
+				"name": "jobs", "config": filepath.Join(dir, "jobs/run"), "autostart": "false (down file)", "autorestart": "false", "state": "run, want down",
+			},
+		},
+		{
+			name:      "s6 service from cmdline",
+			supervise: model.Process{Command: "s6-supervise", Cmdline: "s6-supervise " + filepath.Join(dir, "s6svc")},
+			want: map[string]string{
+				"name": "s6svc", "config": filepath.Join(dir, "s6svc/run"), "autostart": "true", "autorestart": "true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serviceDirDetails([]model.Process{tt.supervise, {Command: "web"}})
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("serviceDirDetails() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -122,7 +122,7 @@ func ResolveName(name string) ([]int, error) {
 			if pid == servicePID {
 				continue
 			}
-			fmt.Printf("[%d] PID %d   %s: process   (manual)\n", idx, pid, safeName)
+			fmt.Printf("[%d] PID %d   %s   (manual)\n", idx, pid, candidateLabel(pid, safeName, "process"))
 			idx++
 		}
 		fmt.Println()
@@ -10,21 +10,18 @@ import (
 	"strings"
 
 	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 )
 
 func ResolveName(name string) ([]int, error) {
 	var procPIDs []int
 
 	// Process name and command line matching (case-insensitive, substring)
-	entries, _ := os.ReadDir("/proc")
+	table := procpkg.Snapshot()
 	lowerName := strings.ToLower(name)
 	selfPid := os.Getpid()
 	parentPid := os.Getppid()
-	for _, e := range entries {
-		pid, err := strconv.Atoi(e.Name())
-		if err != nil {
-			continue
-		}
+	for _, pid := range table.Scan("stat", "cmdline") {
 
 		// Prevent matching the PID itself as a name
 		if lowerName == strconv.Itoa(pid) {
@@ -36,21 +33,17 @@ func ResolveName(name string) ([]int, error) {
 			continue
 		}
 
-		comm, err := os.ReadFile("/proc/" + e.Name() + "/comm")
-		if err == nil {
-			if strings.Contains(strings.ToLower(strings.TrimSpace(string(comm))), lowerName) {
+		if comm := table.Comm(pid); comm != "" {
+			if strings.Contains(strings.ToLower(comm), lowerName) {
 				// Exclude grep-like processes
-				if !strings.Contains(strings.ToLower(string(comm)), "grep") {
+				if !strings.Contains(strings.ToLower(comm), "grep") {
 					procPIDs = append(procPIDs, pid)
 				}
 				continue
 			}
 		}
 
-		cmdline, err := os.ReadFile("/proc/" + e.Name() + "/cmdline")
-		if err == nil {
-			// cmdline is null-separated
-			cmd := strings.ReplaceAll(string(cmdline), "\x00", " ")
+		if cmd := table.Cmdline(pid); cmd != "" {
 			// Exclude self, parent, and grep
 			if strings.Contains(strings.ToLower(cmd), lowerName) &&
 				!strings.Contains(strings.ToLower(cmd), "grep") {
@@ -91,7 +84,7 @@ func ResolveName(name string) ([]int, error) {
 			if pid == servicePID {
 				continue
 			}
-			fmt.Printf("[%d] PID %d   %s: worker process   (manual)\n", idx, pid, safeName)
+			fmt.Printf("[%d] PID %d   %s   (manual)\n", idx, pid, candidateLabel(pid, safeName, "worker process"))
 			idx++
 		}
 		fmt.Println()
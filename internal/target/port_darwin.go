@@ -10,7 +10,9 @@ import (
 	"strings"
 )
 
-func ResolvePort(port int) ([]int, error) {
+// ResolvePort returns the PIDs listening on port. Network namespace scoping
+// only exists on Linux, so netns is ignored here.
+func ResolvePort(port int, netns string) ([]int, error) {
 	// Use lsof to find the process listening on this port
 	// -i TCP:<port> = specific TCP port
 	// -s TCP:LISTEN = only LISTEN state
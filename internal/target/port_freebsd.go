@@ -13,7 +13,9 @@ import (
 	"github.com/pranshuparmar/witr/internal/output"
 )
 
-func ResolvePort(port int) ([]int, error) {
+// ResolvePort returns the PIDs listening on port. Network namespace scoping
+// only exists on Linux, so netns is ignored here.
+func ResolvePort(port int, netns string) ([]int, error) {
 	// Use sockstat to find the process listening on this port
 	// sockstat -4 -l -P tcp -p <port>
 	// sockstat -6 -l -P tcp -p <port>
@@ -6,43 +6,119 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 )
 
-func findSocketInodes(port int) (map[string]bool, error) {
-	inodes := make(map[string]bool)
+// netNamespace is a network namespace whose sockets are read through
+// /proc/<pid>/net of one representative process living in it
+type netNamespace struct {
+	inode uint64
+	pid   int
+	host  bool
+}
 
-	files := []string{"/proc/net/tcp", "/proc/net/tcp6"}
-	targetHex := fmt.Sprintf("%04X", port)
+func (ns netNamespace) netDir() string {
+	if ns.host {
+		return "/proc/self/net"
+	}
+	return "/proc/" + strconv.Itoa(ns.pid) + "/net"
+}
 
-	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
+// hostNetNamespace is the network namespace witr itself runs in
+func hostNetNamespace() netNamespace {
+	inode, _ := procpkg.NetNamespace(os.Getpid())
+	return netNamespace{inode: inode, pid: os.Getpid(), host: true}
+}
+
+// listNetNamespaces returns the host network namespace first, followed
+// by every other distinct network namespace we are allowed to inspect
+func listNetNamespaces() []netNamespace {
+	host := hostNetNamespace()
+	namespaces := []netNamespace{host}
+	seen := map[uint64]bool{host.inode: true}
+
+	for _, pid := range procpkg.Snapshot().PIDs() {
+		inode, ok := procpkg.NetNamespace(pid)
+		if !ok || seen[inode] {
 			continue
 		}
+		seen[inode] = true
+		namespaces = append(namespaces, netNamespace{inode: inode, pid: pid})
+	}
+	return namespaces
+}
 
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines[1:] {
-			fields := strings.Fields(line)
-			if len(fields) < 10 {
-				continue
-			}
+// selectNetNamespaces narrows the namespaces to search for a --netns scope
+func selectNetNamespaces(scope string) ([]netNamespace, error) {
+	switch scope {
+	case "", "host":
+		return []netNamespace{hostNetNamespace()}, nil
+	case "all":
+		return listNetNamespaces(), nil
+	}
 
-			localAddr := fields[1]
-			parts := strings.Split(localAddr, ":")
-			if len(parts) != 2 {
-				continue
-			}
+	id, err := procpkg.ResolveContainerID(scope)
+	if err != nil {
+		return nil, err
+	}
+	// Any of the container's processes leads to its namespace, which may
+	// be shared with others (a pod, --network container:<name>) and first
+	// seen through one of theirs
+	var selected []netNamespace
+	seen := make(map[uint64]bool)
+	for _, pid := range procpkg.Snapshot().PIDs() {
+		if procpkg.ContainerID(pid) != id {
+			continue
+		}
+		inode, ok := procpkg.NetNamespace(pid)
+		if !ok || seen[inode] {
+			continue
+		}
+		seen[inode] = true
+		selected = append(selected, netNamespace{inode: inode, pid: pid})
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no network namespace found for container %q", scope)
+	}
+	return selected, nil
+}
+
+func findSocketInodes(port int, namespaces []netNamespace) (map[string]bool, error) {
+	inodes := make(map[string]bool)
 
-			state := fields[3]
-			if state != "0A" { // 0A is the linux /proc/net/tcp* code for TCP_LISTEN, so we only report actual listeners for --port
+	targetHex := fmt.Sprintf("%04X", port)
+
+	for _, ns := range namespaces {
+		for _, file := range []string{"tcp", "tcp6"} {
+			data, err := os.ReadFile(filepath.Join(ns.netDir(), file))
+			if err != nil {
 				continue
 			}
 
-			if parts[1] == targetHex {
-				inodes[fields[9]] = true
+			lines := strings.Split(string(data), "\n")
+			for _, line := range lines[1:] {
+				fields := strings.Fields(line)
+				if len(fields) < 10 {
+					continue
+				}
+
+				localAddr := fields[1]
+				parts := strings.Split(localAddr, ":")
+				if len(parts) != 2 {
+					continue
+				}
+
+				state := fields[3]
+				if state != "0A" { // 0A is the linux /proc/net/tcp* code for TCP_LISTEN, so we only report actual listeners for --port
+					continue
+				}
+
+				if parts[1] == targetHex {
+					inodes[fields[9]] = true
+				}
 			}
 		}
 	}
@@ -54,48 +130,30 @@ func findSocketInodes(port int) (map[string]bool, error) {
 	return inodes, nil
 }
 
-func ResolvePort(port int) ([]int, error) {
-	inodes, err := findSocketInodes(port)
+// ResolvePort returns the PIDs listening on port within the network namespaces
+// selected by netns: "host", "all", a container name/ID, or "" to search the
+// host first and fall back to all namespaces.
+func ResolvePort(port int, netns string) ([]int, error) {
+	namespaces, err := selectNetNamespaces(netns)
 	if err != nil {
 		return nil, err
 	}
 
-	// collect all owning pids so callers can handle multi-owner sockets.
-	pidSet := make(map[int]bool)
-	procEntries, _ := os.ReadDir("/proc")
-	for _, entry := range procEntries {
-		pid, err := strconv.Atoi(entry.Name())
-		if err != nil {
-			continue
-		}
-
-		fdDir := filepath.Join("/proc", entry.Name(), "fd")
-		fds, err := os.ReadDir(fdDir)
+	inodes, err := findSocketInodes(port, namespaces)
+	if err != nil && netns == "" {
+		// Nothing on the host, the port may be bound inside a container
+		namespaces, _ = selectNetNamespaces("all")
+		inodes, err = findSocketInodes(port, namespaces[1:])
 		if err != nil {
-			continue
-		}
-
-		for _, fd := range fds {
-			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
-			if err != nil {
-				continue
-			}
-
-			if rest, ok := strings.CutPrefix(link, "socket:["); ok {
-				inode, ok := strings.CutSuffix(rest, "]")
-				if ok && inodes[inode] {
-					pidSet[pid] = true
-				}
-			}
+			err = fmt.Errorf("no process listening on port %d in any network namespace", port)
 		}
 	}
-
-	result := make([]int, 0, len(pidSet))
-	for pid := range pidSet {
-		result = append(result, pid)
+	if err != nil {
+		return nil, err
 	}
-	sort.Ints(result)
 
+	// collect all owning pids so callers can handle multi-owner sockets.
+	result := procpkg.Snapshot().SocketOwners(inodes)
 	if len(result) == 0 {
 		return nil, fmt.Errorf("socket found but owning process not detected")
 	}
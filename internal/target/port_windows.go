@@ -9,7 +9,9 @@ import (
 	"strings"
 )
 
-func ResolvePort(port int) ([]int, error) {
+// ResolvePort returns the PIDs listening on port. Network namespace scoping
+// only exists on Linux, so netns is ignored here.
+func ResolvePort(port int, netns string) ([]int, error) {
 	// netstat -ano
 	out, err := exec.Command("netstat", "-ano").Output()
 	if err != nil {
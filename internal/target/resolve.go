@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -24,7 +26,7 @@ func Resolve(t model.Target) ([]int, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid port")
 		}
-		return ResolvePort(port)
+		return ResolvePort(port, t.NetNS)
 
 	case model.TargetName:
 		return ResolveName(val)
@@ -33,3 +35,12 @@ func Resolve(t model.Target) ([]int, error) {
 		return nil, fmt.Errorf("unknown target")
 	}
 }
+
+// candidateLabel describes a process in an ambiguity list, naming the app an
+// interpreter runs (e.g. "python: celery worker") when it can be detected
+func candidateLabel(pid int, safeName, fallback string) string {
+	if app := procpkg.DetectApp(procpkg.CmdlineArgs(pid, procpkg.GetCmdline(pid))); app != "" {
+		return safeName + ": " + output.SanitizeTerminal(app)
+	}
+	return safeName + ": " + fallback
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Binary verification outcomes against the package database
+const (
+	BinaryVerified     = "verified"
+	BinaryModified     = "modified"
+	BinaryUnverifiable = "unverifiable"
+)
+
+// BinaryInfo describes the executable of a process and the package owning it
+type BinaryInfo struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	// Only with --verbose, or when the file does not match its package
+	SHA256 string `json:",omitempty"`
+
+	// Owning package (dpkg, rpm or apk), empty if the file is not packaged
+	PackageManager string `json:",omitempty"`
+	Package        string `json:",omitempty"`
+	PackageVersion string `json:",omitempty"`
+
+	// "verified", "modified" or "unverifiable" (no packaged hash); empty if unowned
+	Verification string `json:",omitempty"`
+
+	// Whether a package database was found to check ownership against
+	PackageDBAvailable bool `json:",omitempty"`
+}
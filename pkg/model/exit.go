@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// signalNames are the Linux signals a process commonly dies from
+var signalNames = map[int]string{
+	1: "SIGHUP", 2: "SIGINT", 3: "SIGQUIT", 4: "SIGILL", 5: "SIGTRAP", 6: "SIGABRT",
+	7: "SIGBUS", 8: "SIGFPE", 9: "SIGKILL", 10: "SIGUSR1", 11: "SIGSEGV", 12: "SIGUSR2",
+	13: "SIGPIPE", 14: "SIGALRM", 15: "SIGTERM", 24: "SIGXCPU", 25: "SIGXFSZ", 31: "SIGSYS",
+}
+
+// SignalName names a Linux signal number, e.g. "SIGKILL (9)"
+func SignalName(sig int) string {
+	if name, ok := signalNames[sig]; ok {
+		return fmt.Sprintf("%s (%d)", name, sig)
+	}
+	return fmt.Sprintf("signal %d", sig)
+}
+
+// OOMKill is an out-of-memory kill found in the kernel log
+type OOMKill struct {
+	// Zero when the log line carries no usable timestamp
+	Time    time.Time
+	PID     int
+	Command string
+	// Memory cgroup whose limit was hit; empty for a system-wide OOM
+	MemCgroup string `json:",omitempty"`
+	// Cgroup of the killed task
+	TaskCgroup string `json:",omitempty"`
+	// Resident anonymous memory at the time of the kill, in kB
+	AnonRSSKB int64 `json:",omitempty"`
+	// Where the kill was found: /dev/kmsg or a log file
+	Source string
+}
+
+// CgroupMemory is the memory limit, usage and OOM record of a process's
+// cgroup: a service or container whose processes keep being OOM-killed
+// shows up here even after the killed ones are gone
+type CgroupMemory struct {
+	Path string
+	// Zero when unlimited
+	MemoryMax     uint64 `json:",omitempty"`
+	MemoryCurrent uint64
+	// memory.events counters (v2): times the limit was hit and the OOM
+	// killer invoked, and processes it killed. v1 only has the latter.
+	OOMEvents uint64 `json:",omitempty"`
+	OOMKills  uint64 `json:",omitempty"`
+	// Kills in the kernel log of processes in this cgroup. The log is only
+	// searched when the counters show kills, or with --verbose.
+	Kills []OOMKill `json:",omitempty"`
+	// Kills of processes running the same command whose log lines name no
+	// cgroup: they may have been anywhere on the host, so they are not
+	// counted as this cgroup's
+	SameCommandKills []OOMKill `json:",omitempty"`
+}
+
+// UnitState is the last known state of a systemd unit and its main process
+type UnitState struct {
+	Name        string
+	LoadState   string
+	ActiveState string
+	SubState    string
+	// Result of the last run: success, exit-code, signal, core-dump,
+	// oom-kill, timeout, watchdog, start-limit-hit, ...
+	Result string `json:",omitempty"`
+	// Currently running main process, 0 if none
+	MainPID int `json:",omitempty"`
+	// Last main process and how it ended: "exited", "killed" or "dumped"
+	// with the exit code or signal number in ExecMainStatus
+	ExecMainPID    int    `json:",omitempty"`
+	ExecMainCode   string `json:",omitempty"`
+	ExecMainStatus int    `json:",omitempty"`
+	ExecMainStart  time.Time
+	ExecMainExit   time.Time
+	// Last change of ActiveState
+	StateChange time.Time
+	NRestarts   int `json:",omitempty"`
+	// Rebuilt from the journal files because systemd no longer knows the
+	// unit (transient, or garbage-collected after it stopped)
+	FromJournal bool `json:",omitempty"`
+}
+
+// ExitReport explains why a process or a systemd unit stopped running
+type ExitReport struct {
+	// The PID or unit asked about
+	Target string
+	PID    int `json:",omitempty"`
+	// Command of the process, if known
+	Command string `json:",omitempty"`
+	// The PID is in use again, or the unit is active again
+	Running bool
+	// Exec and exit events from witr record, if it was running
+	Run  *HistoryRun `json:",omitempty"`
+	Unit *UnitState  `json:",omitempty"`
+	// OOM kills of the process (or in the unit's cgroup), oldest first
+	OOMKills []OOMKill `json:",omitempty"`
+	// One-line conclusion drawn from the above
+	Reason string
+}
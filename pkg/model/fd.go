@@ -0,0 +1,44 @@
+package model
+
+// FDCategory classifies an open file descriptor
+type FDCategory string
+
+const (
+	FDFile   FDCategory = "file"
+	FDDir    FDCategory = "dir"
+	FDPipe   FDCategory = "pipe"
+	FDSocket FDCategory = "socket"
+	FDAnon   FDCategory = "anon"
+	FDDevice FDCategory = "device"
+	FDOther  FDCategory = "other"
+)
+
+// FDCategories lists the categories in display order
+var FDCategories = []FDCategory{FDFile, FDDir, FDPipe, FDSocket, FDAnon, FDDevice, FDOther}
+
+// FileDescriptor is a single classified open file descriptor
+type FileDescriptor struct {
+	FD       int
+	Category FDCategory
+	// Raw link target (e.g. "/var/log/app.log", "pipe:[1234]", "anon_inode:[eventfd]")
+	Target string
+	// Resolved detail: socket protocol and addresses, pipe end, anon inode kind
+	Detail string `json:",omitempty"`
+	// Other processes holding the same pipe
+	Peers []FDPeer `json:",omitempty"`
+}
+
+// FDPeer is a process on the other side of a pipe
+type FDPeer struct {
+	PID     int
+	Command string
+}
+
+// CountFDs returns the number of descriptors per category
+func CountFDs(fds []FileDescriptor) map[FDCategory]int {
+	counts := make(map[FDCategory]int)
+	for _, fd := range fds {
+		counts[fd.Category]++
+	}
+	return counts
+}
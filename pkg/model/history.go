@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// History event types
+const (
+	HistoryExec = "exec"
+	HistoryExit = "exit"
+)
+
+// HistoryEvent is a process exec or exit observed by `witr record`
+type HistoryEvent struct {
+	// Position in the history file, increasing over the recorder's lifetime
+	Seq   uint64
+	Time  time.Time
+	Event string
+	PID   int
+	PPID  int `json:",omitempty"`
+
+	Command    string `json:",omitempty"`
+	Cmdline    string `json:",omitempty"`
+	Exe        string `json:",omitempty"`
+	User       string `json:",omitempty"`
+	WorkingDir string `json:",omitempty"`
+
+	// Parents at exec time, init first, the process itself excluded
+	Ancestry []HistoryProcess `json:",omitempty"`
+
+	// Exit status, exit events from the proc connector only
+	ExitCode   *int `json:",omitempty"`
+	Signal     int  `json:",omitempty"`
+	CoreDumped bool `json:",omitempty"`
+}
+
+// HistoryProcess is an ancestor recorded with an exec event
+type HistoryProcess struct {
+	PID     int
+	Command string
+	Cmdline string `json:",omitempty"`
+}
+
+// ProcessHistory is what the history file knows about a PID: one run per
+// time the PID was used, oldest first
+type ProcessHistory struct {
+	PID int
+	// Whether a process with this PID is running now (possibly a newer one)
+	Running bool
+	Runs    []HistoryRun
+}
+
+// HistoryRun is one process that had the PID: the command it was started
+// as, any later execs, and how it ended
+type HistoryRun struct {
+	Execs []HistoryEvent `json:",omitempty"`
+	Exit  *HistoryEvent  `json:",omitempty"`
+}
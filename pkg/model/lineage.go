@@ -0,0 +1,36 @@
+package model
+
+// Lineage describes whether a process was detached from the session that
+// launched it, and how: nohup, setsid, a double-fork daemon or a job left
+// behind by an exited shell
+type Lineage struct {
+	// Parent exited and the process was adopted by PID 1 or a subreaper
+	Reparented bool
+	// Command of the adopting process (init or a subreaper such as systemd --user)
+	Adopter string `json:",omitempty"`
+	// Started from an interactive shell or login session
+	FromShell bool
+	// Detachment methods found: "nohup", "setsid", "double fork", "background job"
+	Methods []string `json:",omitempty"`
+	// Observations the verdict is based on
+	Evidence []string `json:",omitempty"`
+	// Best guess at the launcher of a reparented process
+	ProbableParent *ProbableParent `json:",omitempty"`
+}
+
+// ProbableParent is the process most likely to have launched a reparented
+// process, inferred from session, process group, login session, cgroup and
+// start time ordering
+type ProbableParent struct {
+	PID     int
+	Command string `json:",omitempty"`
+	Cmdline string `json:",omitempty"`
+	// False when only its PID survives, e.g. as the session id of the process
+	Alive    bool
+	Evidence []string
+}
+
+// Detached reports whether the process outlives or escaped its launcher
+func (l *Lineage) Detached() bool {
+	return l != nil && (l.Reparented || len(l.Methods) > 0)
+}
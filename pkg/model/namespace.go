@@ -0,0 +1,13 @@
+package model
+
+// Namespace describes one Linux namespace a process belongs to
+type Namespace struct {
+	// mnt, pid, net, uts, ipc, user or cgroup
+	Type  string
+	Inode uint64
+
+	// Whether the process is in a different namespace of this type than PID 1
+	DiffersFromInit bool
+	// Whether the process is in a different namespace of this type than its parent
+	DiffersFromParent bool
+}
@@ -11,6 +11,9 @@ type Process struct {
 	StartedAt time.Time
 	User      string
 
+	// Application run by an interpreter (script, module, jar or main class), if any
+	App string `json:",omitempty"`
+
 	WorkingDir string
 	GitRepo    string
 	GitBranch  string
@@ -24,11 +27,40 @@ type Process struct {
 	// Health status ("healthy", "zombie", "stopped", "high-cpu", "high-mem")
 	Health string
 
-	// Forked status ("forked", "not-forked", "unknown")
+	// Forked status ("forked", "not-forked", "unknown"): "forked" for any
+	// process whose parent is not init.
+	//
+	// Deprecated: use Lineage, which tells whether the process was actually
+	// detached from its launcher. Forked will be removed in a future release.
 	Forked string
+
 	// Environment variables (key=value)
 	Env []string
 
+	// Capabilities, seccomp, no_new_privs and LSM label (Linux)
+	Security *SecurityContext `json:",omitempty"`
+
+	// Namespace membership from /proc/<pid>/ns (Linux)
+	Namespaces []Namespace `json:",omitempty"`
+
+	// Executable metadata and package ownership, target process only
+	Binary *BinaryInfo `json:",omitempty"`
+
+	// Whether the process was detached from its launcher (Linux), target process only
+	Lineage *Lineage `json:",omitempty"`
+
+	// Memory limit and OOM kills of the process's cgroup (Linux), target process only
+	MemoryCgroup *CgroupMemory `json:",omitempty"`
+
+	// Audit login identity (Linux), nil when the process has no login session
+	Login *LoginInfo `json:",omitempty"`
+
+	// Executable or shared libraries deleted/replaced on disk while still in use (Linux)
+	ExeDeleted  bool     `json:",omitempty"`
+	DeletedLibs []string `json:",omitempty"`
+	// Package ("name version") now owning each deleted path, keyed by path
+	DeletedOwners map[string]string `json:",omitempty"`
+
 	// Extended information for verbose output
 	Memory      MemoryInfo `json:",omitempty"`
 	IO          IOStats    `json:",omitempty"`
@@ -37,6 +69,10 @@ type Process struct {
 	FDLimit     uint64     `json:",omitempty"`
 	Children    []int      `json:",omitempty"`
 	ThreadCount int        `json:",omitempty"`
+
+	// Classified descriptors with per-category counts (Linux)
+	FDs      []FileDescriptor   `json:",omitempty"`
+	FDCounts map[FDCategory]int `json:",omitempty"`
 }
 
 // MemoryInfo contains detailed memory information
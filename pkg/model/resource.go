@@ -14,9 +14,39 @@ type ResourceContext struct {
 	// CPU scheduling priority if throttled
 	AppNapped bool
 
-	// CPU usage percentage (0-100)
+	// CPU usage as a percentage of one CPU: a process busy on several CPUs
+	// exceeds 100
 	CPUUsage float64
 
 	// Memory usage in bytes
 	MemoryUsage uint64
+
+	// OOM killer badness score (0-1000) and its adjustment (-1000..1000), Linux only
+	OOMScore    int
+	OOMScoreAdj int
+
+	// Limits and usage of the process's cgroup (Linux)
+	Cgroup *CgroupResources `json:",omitempty"`
+}
+
+// CgroupResources holds the limits, usage and pressure of a cgroup
+type CgroupResources struct {
+	Path string
+
+	// memory.max / memory.current in bytes, MemoryMax 0 means unlimited
+	MemoryMax     uint64
+	MemoryCurrent uint64
+
+	// CPUs allowed by cpu.max (quota/period), 0 means unlimited
+	CPUQuota float64
+
+	// cpu.stat throttling counters
+	NrPeriods     uint64
+	NrThrottled   uint64
+	ThrottledUsec uint64
+
+	// Pressure stall information ("some" avg10, in percent)
+	CPUPressure    float64
+	MemoryPressure float64
+	IOPressure     float64
 }
@@ -1,21 +1,46 @@
 package model
 
+import "time"
+
 type Result struct {
 	Target         Target
 	ResolvedTarget string
 	Process        Process
-	RestartCount   int
+	// Restarts counted by the supervisor: systemd NRestarts, the container's
+	// RestartCount or pm2's restart_time
+	RestartCount int
+	// Where RestartCount comes from; empty when nothing counts restarts
+	RestartSource string `json:",omitempty"`
+	// When the supervisor started, for supervisors that keep no count
+	// (supervisord, runit, s6): a program that started well after its
+	// supervisor was restarted, or started by hand, since
+	SupervisorStarted *time.Time `json:",omitempty"`
+	// Ancestors running the same command as their parent, e.g. a script
+	// re-running itself or a pre-forking server; these are not restarts
+	NestedSameName int `json:",omitempty"`
 	Ancestry       []Process
 	ChildProcesses []Process `json:",omitempty"`
 	Source         Source
-	Warnings       []string
+	Warnings       []Warning
+	// Warnings hidden by the allowlist (only with --show-suppressed)
+	Suppressed []Warning `json:",omitempty"`
 
 	// SocketInfo holds socket state details (for port queries)
 	SocketInfo *SocketInfo
 
-	// ResourceContext holds resource usage context (macOS)
+	// ResourceContext holds resource usage context (CPU, cgroup limits, macOS power state)
 	ResourceContext *ResourceContext
 
 	// FileContext holds file descriptor and lock info
 	FileContext *FileContext
+
+	// Session is the interactive login the process was started from, if any
+	Session *Session `json:",omitempty"`
+
+	// Processes the /proc scans could not read, because they were
+	// unreadable or timed out; results drawn from the scans may be partial
+	ScanIncomplete int `json:",omitempty"`
+	// Processes left out because permissions did not allow reading them,
+	// normally those of other users when not running as root
+	ScanDenied int `json:",omitempty"`
 }
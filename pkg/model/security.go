@@ -0,0 +1,35 @@
+package model
+
+import "strings"
+
+// SecurityContext holds the security posture of a process (Linux)
+type SecurityContext struct {
+	// Effective user ID
+	UID int
+
+	// Capability sets, decoded to names (e.g. "CAP_NET_BIND_SERVICE")
+	CapEff []string
+	CapPrm []string
+	CapBnd []string
+
+	// Seccomp mode: "disabled", "strict" or "filter"
+	Seccomp string
+
+	// Whether the process (and its children) can never gain privileges
+	NoNewPrivs bool
+
+	// AppArmor profile or SELinux context, "" if no LSM is active
+	LSMLabel string
+}
+
+// Confined reports whether a seccomp filter or an LSM profile restricts the process
+func (s *SecurityContext) Confined() bool {
+	if s == nil {
+		return false
+	}
+	if s.Seccomp != "" && s.Seccomp != "disabled" {
+		return true
+	}
+	// AppArmor reports "unconfined" or "<profile> (complain)", SELinux "...:unconfined_t:..."
+	return s.LSMLabel != "" && s.LSMLabel != "kernel" && !strings.Contains(s.LSMLabel, "unconfined") && !strings.HasSuffix(s.LSMLabel, "(complain)")
+}
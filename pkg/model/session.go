@@ -0,0 +1,25 @@
+package model
+
+// LoginInfo is the audit login identity of a process (Linux loginuid and
+// sessionid). It is inherited by children and kept across sudo, su and
+// daemonizing, so it still names the user who logged in.
+type LoginInfo struct {
+	UID       int
+	User      string
+	SessionID int
+}
+
+// Session describes the interactive login a process was started from
+type Session struct {
+	// User who logged in, before any sudo/su
+	User string `json:",omitempty"`
+	// Remote address of the SSH connection
+	RemoteAddr string `json:",omitempty"`
+	// Audit session id (Linux)
+	SessionID int `json:",omitempty"`
+	// User switches on the way to the process, in order (sudo, su, doas)
+	Via []string `json:",omitempty"`
+	// Terminal multiplexer (tmux, screen) and its session name
+	Multiplexer        string `json:",omitempty"`
+	MultiplexerSession string `json:",omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// StaleProcess is a process still running a binary or libraries that were
+// deleted or replaced on disk, typically by a package upgrade
+type StaleProcess struct {
+	PID     int
+	Command string
+	User    string
+	// systemd unit owning the process, if any
+	Unit string `json:",omitempty"`
+	// User whose manager (systemd --user) runs Unit; empty for system units
+	UnitUser    string `json:",omitempty"`
+	Exe         string
+	ExeDeleted  bool     `json:",omitempty"`
+	DeletedLibs []string `json:",omitempty"`
+	// Package ("name version") now owning each deleted path, keyed by path
+	DeletedOwners map[string]string `json:",omitempty"`
+}
@@ -11,4 +11,7 @@ const (
 type Target struct {
 	Type  TargetType
 	Value string
+	// Network namespace scope for port targets: "" (host, then all), "host", "all"
+	// or a container name/ID (Linux)
+	NetNS string `json:",omitempty"`
 }
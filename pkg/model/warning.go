@@ -0,0 +1,35 @@
+package model
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarn     Severity = "warn"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank orders severities so they can be compared (info < warn < critical).
+// Unknown severities rank below info.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarn:
+		return 2
+	case SeverityCritical:
+		return 3
+	}
+	return 0
+}
+
+// Warning is a single non-blocking observation about the target process
+type Warning struct {
+	// Stable rule identifier (e.g. "root-user", "public-bind")
+	ID       string
+	Severity Severity
+	Message  string
+	// Raw value that triggered the rule, if any
+	Evidence string `json:",omitempty"`
+	// Why the warning was suppressed by the allowlist, if it was
+	SuppressReason string `json:",omitempty"`
+}